@@ -1,121 +1,279 @@
 package app
 
 import (
-	"database/sql"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/IceMAN2377/market"
 	"github.com/IceMAN2377/market/internal/config"
+	"github.com/IceMAN2377/market/internal/health"
+	"github.com/IceMAN2377/market/internal/i18n"
+	"github.com/IceMAN2377/market/internal/notify"
 	"github.com/IceMAN2377/market/internal/repository/postgres"
+	"github.com/IceMAN2377/market/internal/service/budget"
+	"github.com/IceMAN2377/market/internal/service/catalog"
 	"github.com/IceMAN2377/market/internal/service/subscription"
-	"github.com/golang-migrate/migrate/v4"
+	"github.com/IceMAN2377/market/internal/tracing"
 	"github.com/jmoiron/sqlx"
+	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
-	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
 
 	v1Http "github.com/IceMAN2377/market/internal/transport/http"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-
-	_ "github.com/lib/pq"
-)
-
-const (
-	migrationsPath = "db/migrations"
-	dbDriverName   = "postgres"
 )
 
 type App struct {
-	router *http.ServeMux
-	port   int
-	logger *slog.Logger
+	router          *http.ServeMux
+	server          *http.Server
+	db              *sqlx.DB
+	port            int
+	logger          *slog.Logger
+	serviceName     string
+	health          *health.Checker
+	shutdownDelay   time.Duration
+	serving         atomic.Bool // listener открыт, сервер принимает соединения
+	requestTimeout  time.Duration
+	maxBodyBytes    int64
+	validator       *v1Http.OpenAPIValidator
+	messages        *i18n.Bundle
+	scheduler       *scheduler
+	shutdownTracing tracing.ShutdownFunc
 }
 
 func NewApp(config *config.Config, logger *slog.Logger) *App {
-	// Строка подключения к PostgreSQL
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		config.PostgresHost, config.PostgresPort, config.PostgresUser,
-		config.PostgresPassword, config.PostgresDb, config.PostgresSslMode)
+	// Инициализация трассировки
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName:  config.ServiceName,
+		Exporter:     config.TracingExporter,
+		OTLPEndpoint: config.TracingOTLPEndpoint,
+		OTLPInsecure: config.TracingOTLPInsecure,
+		FilePath:     config.TracingFile,
+		SampleRatio:  config.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing", "error", err)
+		panic("failed to initialize tracing: " + err.Error())
+	}
 
 	// Подключение к базе данных
-	db, err := sql.Open(dbDriverName, psqlInfo)
+	psql, err := OpenDB(config)
 	if err != nil {
 		logger.Error("Failed to connect to database", "error", err)
-		panic("failed to connect to db: " + err.Error())
-	}
-
-	// Проверка соединения
-	if err := db.Ping(); err != nil {
-		logger.Error("Failed to ping database", "error", err)
-		panic("failed to ping database: " + err.Error())
+		panic(err.Error())
 	}
 
-	psql := sqlx.NewDb(db, dbDriverName)
-
-	// Применение миграций
+	// Применение миграций при старте (для разработки; в проде — `market migrate up`)
 	if config.PostgresMigrate {
-		dbURI := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-			config.PostgresUser, url.QueryEscape(config.PostgresPassword),
-			config.PostgresHost, config.PostgresPort, config.PostgresDb, config.PostgresSslMode)
-
-		m, err := migrate.New("file://"+migrationsPath, dbURI)
-		if err != nil {
-			logger.Error("Failed to create migrate object", "error", err)
-			panic("failed to create migrate object: " + err.Error())
-		}
-
-		if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		if err := MigrateUp(config); err != nil {
 			logger.Error("Failed to apply migrations", "error", err)
-			panic("failed to apply migrations: " + err.Error())
+			panic(err.Error())
 		}
 
 		logger.Info("Database migrations applied successfully")
 	}
 
+	// Ожидаемая версия схемы для проверки готовности
+	expectedVersion, err := latestMigrationVersion(config)
+	if err != nil {
+		logger.Error("Failed to read migrations", "error", err)
+		panic("failed to read migrations: " + err.Error())
+	}
+
+	// Спецификация OpenAPI
+	swaggerSpec, err := loadSwaggerSpec(config)
+	if err != nil {
+		logger.Error("Failed to load swagger spec", "error", err)
+		panic(err.Error())
+	}
+	swaggerDocs, err := v1Http.NewSwaggerDocs(swaggerSpec)
+	if err != nil {
+		logger.Error("Failed to prepare swagger docs", "error", err)
+		panic(err.Error())
+	}
+	validator, err := v1Http.NewOpenAPIValidator(swaggerSpec, logger, v1Http.OpenAPIValidatorOptions{
+		ValidateResponses: config.OpenAPIValidateResponses,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize openapi validation", "error", err)
+		panic(err.Error())
+	}
+
+	// Каталоги сообщений для локализации ответов
+	messages, err := loadMessages(config)
+	if err != nil {
+		logger.Error("Failed to load message catalogs", "error", err)
+		panic(err.Error())
+	}
+
 	// Инициализация слоев приложения
-	repo := postgres.NewRepository(psql)
-	service := subscription.NewService(repo)
+	repo := postgres.NewRepository(psql, logger)
+	budgets := budget.NewService(repo, newNotifier(config, logger), config.BudgetThresholds, logger)
+	service := subscription.NewService(repo, config.DateBounds(), budgets)
+	serviceCatalog := catalog.NewService(repo)
+	checker := newHealthChecker(psql, config.HealthCheckTimeout, expectedVersion)
+
+	// Фоновые задачи: фиксация истекших подписок, автопродление, новые цены и проверка бюджетов
+	var jobs *scheduler
+	if config.SchedulerEnabled {
+		jobs = newScheduler(psql, logger, config.SchedulerJobTimeout)
+		jobs.add(job{name: "renew_subscriptions", interval: config.JobRenewInterval, run: service.RenewSubscriptions})
+		jobs.add(job{name: "expire_subscriptions", interval: config.JobExpireInterval, run: service.ExpireSubscriptions})
+		jobs.add(job{name: "apply_price_changes", interval: config.JobPriceInterval, run: service.ApplyPriceChanges})
+		jobs.add(job{name: "evaluate_budgets", interval: config.JobBudgetInterval, run: budgets.EvaluateBudgets})
+	}
 	router := http.NewServeMux()
 
 	// Регистрация HTTP endpoints
-	v1Http.RegisterEndpoints(logger, router, service)
+	v1Http.RegisterEndpoints(logger, router, service, budgets, serviceCatalog, checker, swaggerDocs)
 
 	logger.Info("Application initialized successfully")
 
-	return &App{
-		router: router,
-		port:   config.HttpPort,
-		logger: logger,
+	app := &App{
+		router:          router,
+		db:              psql,
+		port:            config.HttpPort,
+		logger:          logger,
+		serviceName:     config.ServiceName,
+		health:          checker,
+		shutdownDelay:   config.ShutdownDelay,
+		requestTimeout:  config.HttpRequestTimeout,
+		maxBodyBytes:    config.HttpMaxBodyBytes,
+		messages:        messages,
+		scheduler:       jobs,
+		shutdownTracing: shutdownTracing,
 	}
+	if config.OpenAPIValidateRequests {
+		app.validator = validator
+	}
+
+	// Порядок middleware: трассировка -> request ID и access-лог -> язык -> recovery -> лимиты -> OpenAPI -> роутер
+	handler := app.addRouteNameMiddleware(app.router)
+	if app.validator != nil {
+		handler = app.validator.Middleware(handler)
+	}
+	handler = app.addLimitsMiddleware(handler)
+	handler = app.addRecoveryMiddleware(handler)
+	handler = app.addLanguageMiddleware(handler)
+	handler = app.addLoggingMiddleware(handler)
+	handler = app.addTracingMiddleware(handler)
+
+	app.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", app.port),
+		Handler:           handler,
+		ReadTimeout:       config.HttpReadTimeout,
+		ReadHeaderTimeout: config.HttpReadHeaderTimeout,
+		WriteTimeout:      config.HttpWriteTimeout,
+		IdleTimeout:       config.HttpIdleTimeout,
+		MaxHeaderBytes:    1 << 20, // 1 MB
+	}
+
+	return app
 }
 
+// Run запускает фоновые задачи и HTTP сервер и блокируется до его остановки через Shutdown
 func (a *App) Run() error {
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", a.port),
-		Handler:        a.addLoggingMiddleware(a.router),
-		MaxHeaderBytes: 1 << 20, // 1 MB
+	if a.scheduler != nil {
+		a.scheduler.start()
 	}
 
-	a.logger.Info("HTTP server starting", "addr", server.Addr)
+	a.logger.Info("HTTP server starting", "addr", a.server.Addr)
 
-	if err := server.ListenAndServe(); err != nil {
+	addr := a.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
 		return fmt.Errorf("server failed to start: %w", err)
 	}
+	a.serving.Store(true)
+
+	if err := a.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
 
 	return nil
 }
 
-// addLoggingMiddleware добавляет middleware для логирования HTTP запросов
-func (a *App) addLoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		a.logger.Info("HTTP request",
-			"method", r.Method,
-			"url", r.URL.Path,
-			"remote_addr", r.RemoteAddr,
-			"user_agent", r.UserAgent(),
-		)
-
-		next.ServeHTTP(w, r)
-	})
+// Shutdown корректно останавливает приложение: снимает сервис с балансировки,
+// дожидается завершения активных запросов, останавливает фоновые задачи,
+// закрывает пул соединений с БД и сбрасывает накопленные спаны. Время остановки ограничено ctx.
+func (a *App) Shutdown(ctx context.Context) error {
+	// Проверка готовности начинает возвращать 503 — балансировщик снимает трафик
+	a.health.SetShuttingDown()
+
+	// Даем балансировщику время заметить неготовность до закрытия listener'а.
+	// Если сервер так и не начал принимать соединения (например, порт занят),
+	// снимать нечего и ждать незачем
+	if a.shutdownDelay > 0 && a.serving.Load() {
+		a.logger.Info("Waiting for load balancer to drain traffic", "delay", a.shutdownDelay)
+		select {
+		case <-time.After(a.shutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	var errs []error
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown http server: %w", err))
+	}
+
+	if a.scheduler != nil {
+		if err := a.scheduler.stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := a.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+	}
+
+	if err := a.shutdownTracing(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown tracing: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// newNotifier создает канал доставки оповещений о бюджетах из конфигурации
+func newNotifier(config *config.Config, logger *slog.Logger) notify.Notifier {
+	if config.Notifier == "webhook" {
+		return notify.NewWebhookNotifier(config.NotifyWebhookURL, config.NotifyWebhookSecret, config.NotifyWebhookTimeout)
+	}
+	return notify.NewLogNotifier(logger)
+}
+
+// loadSwaggerSpec возвращает встроенную спецификацию OpenAPI либо,
+// если задан SWAGGER_PATH, спецификацию из файла на диске (для разработки)
+func loadSwaggerSpec(config *config.Config) ([]byte, error) {
+	if config.SwaggerPath == "" {
+		return market.SwaggerSpec, nil
+	}
+
+	spec, err := os.ReadFile(config.SwaggerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swagger spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+// loadMessages загружает встроенные каталоги сообщений либо, если задан
+// LOCALES_PATH, каталоги из директории на диске (для разработки)
+func loadMessages(config *config.Config) (*i18n.Bundle, error) {
+	var locales fs.FS = os.DirFS(config.LocalesPath)
+	if config.LocalesPath == "" {
+		sub, err := fs.Sub(market.Locales, market.LocalesDir)
+		if err != nil {
+			return nil, err
+		}
+		locales = sub
+	}
+
+	return i18n.NewBundle(locales, config.DefaultLanguage)
 }
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/health"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// newTestApp собирает App без подключения к базе: sqlx.Open не устанавливает соединение
+func newTestApp(t *testing.T, addr string, shutdownDelay time.Duration) *App {
+	db, err := sqlx.Open("postgres", "host=localhost")
+	if err != nil {
+		t.Fatalf("sqlx.Open() error = %v", err)
+	}
+	return &App{
+		server:          &http.Server{Addr: addr, Handler: http.NotFoundHandler()},
+		db:              db,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		health:          health.NewChecker(time.Second),
+		shutdownDelay:   shutdownDelay,
+		shutdownTracing: func(ctx context.Context) error { return nil },
+	}
+}
+
+func TestShutdownSkipsDelayWhenNotServing(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	defer busy.Close()
+
+	app := newTestApp(t, busy.Addr().String(), time.Minute)
+	if err := app.Run(); err == nil {
+		t.Fatal("Run() error = nil, want the port in use")
+	}
+
+	start := time.Now()
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Shutdown() took %s, want no drain delay", elapsed)
+	}
+}
+
+func TestShutdownWaitsDelayWhenServing(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	app := newTestApp(t, "127.0.0.1:0", delay)
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Run() }()
+	for !app.serving.Load() {
+		select {
+		case err := <-runErr:
+			t.Fatalf("Run() error = %v", err)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	start := time.Now()
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("Shutdown() took %s, want at least the %s drain delay", elapsed, delay)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("Run() error = %v, want nil after shutdown", err)
+	}
+}
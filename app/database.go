@@ -0,0 +1,97 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/IceMAN2377/market"
+	"github.com/IceMAN2377/market/internal/config"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	_ "github.com/lib/pq"
+)
+
+const dbDriverName = "postgres"
+
+// OpenDB подключается к PostgreSQL и проверяет соединение
+func OpenDB(config *config.Config) (*sqlx.DB, error) {
+	// Строка подключения к PostgreSQL
+	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		config.PostgresHost, config.PostgresPort, config.PostgresUser,
+		config.PostgresPassword, config.PostgresDb, config.PostgresSslMode)
+
+	db, err := sql.Open(dbDriverName, psqlInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to db: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return sqlx.NewDb(db, dbDriverName), nil
+}
+
+// NewMigrator создает объект golang-migrate для управления схемой БД.
+// Вызывающий должен закрыть его через Close.
+func NewMigrator(config *config.Config) (*migrate.Migrate, error) {
+	dbURI := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		config.PostgresUser, url.QueryEscape(config.PostgresPassword),
+		config.PostgresHost, config.PostgresPort, config.PostgresDb, config.PostgresSslMode)
+
+	src, err := openMigrationSource(config)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("migrations", src, dbURI)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to create migrate object: %w", err)
+	}
+
+	return m, nil
+}
+
+// openMigrationSource открывает встроенные в бинарник миграции либо,
+// если задан MIGRATIONS_PATH, миграции из каталога на диске (для разработки)
+func openMigrationSource(config *config.Config) (source.Driver, error) {
+	var (
+		src source.Driver
+		err error
+	)
+	if config.MigrationsPath != "" {
+		src, err = source.Open("file://" + config.MigrationsPath)
+	} else {
+		src, err = iofs.New(market.Migrations, market.MigrationsDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+
+	return src, nil
+}
+
+// MigrateUp применяет все непримененные миграции
+func MigrateUp(config *config.Config) error {
+	m, err := NewMigrator(config)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/config"
+	"github.com/IceMAN2377/market/internal/health"
+	"github.com/jmoiron/sqlx"
+)
+
+// newHealthChecker создает проверки готовности: доступность базы данных
+// и схема не старше последней известной миграции. Более новая схема
+// допустима: при развертывании миграции применяются раньше, чем заменяются
+// все реплики, и старые реплики должны продолжать принимать запросы.
+func newHealthChecker(db *sqlx.DB, timeout time.Duration, expectedVersion uint) *health.Checker {
+	checker := health.NewChecker(timeout)
+
+	checker.AddCheck("database", func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+
+	checker.AddCheck("migrations", func(ctx context.Context) error {
+		var (
+			version uint
+			dirty   bool
+		)
+		err := db.QueryRowxContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+		if err != nil {
+			return fmt.Errorf("failed to read migration version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < expectedVersion {
+			return fmt.Errorf("schema version %d, expected at least %d", version, expectedVersion)
+		}
+		return nil
+	})
+
+	return checker
+}
+
+// latestMigrationVersion возвращает номер последней миграции в источнике
+func latestMigrationVersion(config *config.Config) (uint, error) {
+	src, err := openMigrationSource(config)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read first migration: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
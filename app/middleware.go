@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+	"unicode"
+
+	"github.com/IceMAN2377/market/internal/i18n"
+	"github.com/IceMAN2377/market/internal/logging"
+	v1Http "github.com/IceMAN2377/market/internal/transport/http"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// addTracingMiddleware создает серверный спан на каждый запрос с поддержкой W3C traceparent
+func (a *App) addTracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, a.serviceName)
+}
+
+// addRouteNameMiddleware переименовывает серверный спан по шаблону маршрута.
+// ServeMux заполняет r.Pattern только у переданного ему запроса, поэтому
+// middleware должен оборачивать роутер непосредственно.
+func (a *App) addRouteNameMiddleware(router *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r)
+
+		if r.Pattern != "" {
+			trace.SpanFromContext(r.Context()).SetName(r.Pattern)
+		}
+	})
+}
+
+// addLoggingMiddleware присваивает запросу X-Request-ID и после его обработки
+// пишет одну строку access-лога со статусом, размером ответа и длительностью
+func (a *App) addLoggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		r = r.WithContext(ctx)
+		w.Header().Set(requestIDHeader, requestID)
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		a.logger.InfoContext(ctx, "HTTP request",
+			"method", r.Method,
+			"url", r.URL.Path,
+			"status", rw.status,
+			"bytes", rw.bytes,
+			"duration_ms", time.Since(started).Milliseconds(),
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// addLanguageMiddleware выбирает язык ответа по Accept-Language и сохраняет
+// локализатор в контексте запроса для сообщений об ошибках
+func (a *App) addLanguageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		localizer := a.messages.Localizer(r.Header.Get("Accept-Language"))
+
+		w.Header().Set("Content-Language", localizer.Language())
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocalizer(r.Context(), localizer)))
+	})
+}
+
+// addRecoveryMiddleware перехватывает панику в обработчике, пишет стек в лог
+// и отвечает клиенту 500 в стандартном формате ошибки
+func (a *App) addRecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// Штатный способ прервать ответ — пробрасываем дальше в net/http
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			a.logger.ErrorContext(r.Context(), "panic recovered",
+				"panic", rec,
+				"stack", string(debug.Stack()),
+			)
+
+			// Если заголовки уже отправлены, корректный JSON ответ сформировать нельзя
+			if rw, ok := w.(*responseWriter); ok && rw.wroteHeader {
+				return
+			}
+			v1Http.ResponseWithProblem(a.logger, w, r, fmt.Errorf("panic: %v", rec))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// addLimitsMiddleware ограничивает размер тела запроса и время его обработки.
+// Дедлайн контекста распространяется на SQL-запросы в репозитории.
+func (a *App) addLimitsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.maxBodyBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, a.maxBodyBytes)
+		}
+
+		if a.requestTimeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), a.requestTimeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validRequestID проверяет, что входящий идентификатор запроса можно безопасно
+// использовать в логах и заголовках ответа
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// responseWriter запоминает статус и количество записанных байт ответа
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Время на снятие advisory lock после запуска задачи
+const unlockTimeout = 5 * time.Second
+
+var schedulerTracer = tracing.Tracer("github.com/IceMAN2377/market/app/scheduler")
+
+// job периодическая фоновая задача. Run возвращает число обработанных записей.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) (int, error)
+}
+
+// scheduler запускает фоновые задачи по интервалам. Перед каждым запуском
+// задача берет advisory lock в PostgreSQL: в нескольких репликах ее
+// выполняет одна, остальные пропускают запуск до следующего интервала.
+type scheduler struct {
+	db      *sqlx.DB
+	logger  *slog.Logger
+	timeout time.Duration
+	jobs    []job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newScheduler создает планировщик; timeout ограничивает один запуск задачи
+func newScheduler(db *sqlx.DB, logger *slog.Logger, timeout time.Duration) *scheduler {
+	return &scheduler{
+		db:      db,
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+// add регистрирует задачу; вызывается до start
+func (s *scheduler) add(j job) {
+	s.jobs = append(s.jobs, j)
+}
+
+// start запускает задачи: каждая выполняется сразу и затем раз в свой интервал
+func (s *scheduler) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.logger.Info("Background job scheduled", "job", j.name, "interval", j.interval)
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+}
+
+// stop прерывает выполняющиеся задачи и дожидается их завершения, но не дольше ctx
+func (s *scheduler) stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background jobs did not stop: %w", ctx.Err())
+	}
+}
+
+func (s *scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, j)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce выполняет задачу, если удалось взять ее блокировку
+func (s *scheduler) runOnce(ctx context.Context, j job) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	ctx, span := schedulerTracer.Start(ctx, "scheduler."+j.name, trace.WithSpanKind(trace.SpanKindInternal))
+	var err error
+	defer tracing.End(span, &err)
+
+	started := time.Now()
+	acquired, count, err := s.withLock(ctx, lockKey(j.name), j.run)
+	span.SetAttributes(
+		attribute.Bool("scheduler.lock_acquired", acquired),
+		attribute.Int("scheduler.processed", count),
+	)
+
+	switch {
+	case err != nil:
+		s.logger.ErrorContext(ctx, "Background job failed", "job", j.name, "error", err)
+	case !acquired:
+		s.logger.DebugContext(ctx, "Background job is running on another instance", "job", j.name)
+	default:
+		s.logger.InfoContext(ctx, "Background job completed", "job", j.name,
+			"processed", count, "duration_ms", time.Since(started).Milliseconds())
+	}
+}
+
+// withLock выполняет run под сессионной advisory lock. Блокировка принадлежит
+// соединению, поэтому берется и снимается на выделенном соединении из пула.
+func (s *scheduler) withLock(ctx context.Context, key int64, run func(context.Context) (int, error)) (acquired bool, count int, err error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.GetContext(ctx, &acquired, `SELECT pg_try_advisory_lock($1)`, key); err != nil {
+		return false, 0, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	if !acquired {
+		return false, 0, nil
+	}
+
+	defer func() {
+		// Контекст задачи может быть уже отменен, блокировку снимаем в любом случае
+		unlockCtx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+
+		if _, unlockErr := conn.ExecContext(unlockCtx, `SELECT pg_advisory_unlock($1)`, key); unlockErr != nil {
+			s.logger.Error("Failed to release job lock, dropping connection", "error", unlockErr)
+			// Соединение с неснятой блокировкой не должно вернуться в пул:
+			// закрытие сессии освобождает блокировку на стороне PostgreSQL
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	count, err = run(ctx)
+	return true, count, err
+}
+
+// lockKey выводит ключ advisory lock из имени задачи
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("market.job." + name))
+	return int64(h.Sum64())
+}
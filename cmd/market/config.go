@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// configCommand обрабатывает `market config print`
+func configCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: market config print [flags]")
+		return exitUsage
+	}
+
+	fs, loader := newFlagSet("config print")
+	cfg, ok := loadConfig(fs, loader, args[1:])
+	if !ok {
+		return exitUsage
+	}
+
+	// Вывод итоговой конфигурации со скрытыми секретами
+	data, err := cfg.YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to encode config:", err)
+		return exitError
+	}
+
+	os.Stdout.Write(data)
+	return exitOK
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/IceMAN2377/market/internal/models"
+	"os"
+)
+
+// costCommand рассчитывает стоимость подписок за период
+func costCommand(args []string) int {
+	fs, loader := newFlagSet("cost")
+	from := fs.String("from", "", "period start: MM-YYYY, YYYY-MM or YYYY-MM-DD (required)")
+	to := fs.String("to", "", "period end, inclusive: MM-YYYY, YYYY-MM or YYYY-MM-DD (required)")
+	userID := fs.String("user", "", "user UUID")
+	serviceName := fs.String("service", "", "service name")
+	prorate := fs.Bool("prorate", false, "charge partial months proportionally to days")
+	cfg, ok := loadConfig(fs, loader, args)
+	if !ok {
+		return exitUsage
+	}
+	if *from == "" || *to == "" {
+		fmt.Fprintln(os.Stderr, "usage: market cost --from DATE --to DATE [--user UUID] [--service NAME] [--prorate]")
+		return exitUsage
+	}
+	logger := newLogger(cfg, os.Stderr)
+
+	svc, closeDB, err := newService(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize service", "error", err)
+		return exitError
+	}
+	defer closeDB()
+
+	req := &models.CostCalculationRequest{
+		StartDate: *from,
+		EndDate:   *to,
+		Prorate:   *prorate,
+	}
+	if *userID != "" {
+		req.UserID = userID
+	}
+	if *serviceName != "" {
+		req.ServiceName = serviceName
+	}
+
+	response, err := svc.CalculateCost(context.Background(), req)
+	if err != nil {
+		logger.Error("Failed to calculate cost", "error", err)
+		return exitError
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(response); err != nil {
+		logger.Error("Failed to write result", "error", err)
+		return exitError
+	}
+	return exitOK
+}
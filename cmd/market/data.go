@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/service"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Колонки CSV при импорте и экспорте. Метки записываются в одну колонку через
+// запятую. Колонки id, service_id, status, created_at и updated_at только
+// выгружаются: при импорте подписка создается заново, сервис каталога
+// находится по названию.
+var csvHeader = []string{
+	"id", "service_name", "service_id", "price", "user_id", "start_date", "end_date",
+	"status", "auto_renew", "billing_period", "category", "tags", "notes", "created_at", "updated_at",
+}
+
+// Разделитель меток в колонке tags
+const csvTagSeparator = ","
+
+// importCommand импортирует подписки из JSON или CSV файла через слой сервиса,
+// поэтому к каждой записи применяется та же валидация, что и в API
+func importCommand(args []string) int {
+	fs, loader := newFlagSet("import")
+	allowOverlap := fs.Bool("allow-overlap", false, "import subscriptions that overlap existing ones of the same user and service")
+	cfg, ok := loadConfig(fs, loader, args)
+	if !ok {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: market import [flags] <file.json|file.csv>")
+		return exitUsage
+	}
+	path := fs.Arg(0)
+	logger := newLogger(cfg, os.Stderr)
+
+	requests, err := readImportFile(path)
+	if err != nil {
+		logger.Error("Failed to read import file", "file", path, "error", err)
+		return exitError
+	}
+
+	svc, closeDB, err := newService(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize service", "error", err)
+		return exitError
+	}
+	defer closeDB()
+
+	ctx := context.Background()
+	failed := 0
+	for i := range requests {
+		requests[i].AllowOverlap = *allowOverlap
+		if _, err := svc.CreateSubscription(ctx, &requests[i]); err != nil {
+			failed++
+			logger.Error("Failed to import subscription", "record", i+1, "error", err)
+		}
+	}
+
+	logger.Info("Import finished", "imported", len(requests)-failed, "failed", failed)
+	if failed > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func readImportFile(path string) ([]models.CreateSubscriptionRequest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var requests []models.CreateSubscriptionRequest
+		if err := json.NewDecoder(file).Decode(&requests); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return requests, nil
+	case ".csv":
+		return readImportCSV(file)
+	default:
+		return nil, fmt.Errorf("unsupported file format %q, expected .json or .csv", filepath.Ext(path))
+	}
+}
+
+// readImportCSV читает CSV с заголовком; порядок колонок произвольный,
+// лишние колонки (id, created_at, ...) игнорируются
+func readImportCSV(r io.Reader) ([]models.CreateSubscriptionRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"service_name", "price", "user_id", "start_date"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required CSV column %q", name)
+		}
+	}
+
+	get := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var requests []models.CreateSubscriptionRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		price, err := strconv.Atoi(get(record, "price"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+		}
+
+		req := models.CreateSubscriptionRequest{
+			ServiceName: get(record, "service_name"),
+			Price:       price,
+			UserID:      get(record, "user_id"),
+			StartDate:   get(record, "start_date"),
+		}
+		if endDate := get(record, "end_date"); endDate != "" {
+			req.EndDate = &endDate
+		}
+		if autoRenew := get(record, "auto_renew"); autoRenew != "" {
+			req.AutoRenew, err = strconv.ParseBool(autoRenew)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid auto_renew: %w", line, err)
+			}
+		}
+		req.BillingPeriod = models.BillingPeriod(get(record, "billing_period"))
+		if category := get(record, "category"); category != "" {
+			req.Category = &category
+		}
+		if tags := get(record, "tags"); tags != "" {
+			for _, tag := range strings.Split(tags, csvTagSeparator) {
+				req.Tags = append(req.Tags, strings.TrimSpace(tag))
+			}
+		}
+		if notes := get(record, "notes"); notes != "" {
+			req.Notes = &notes
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+// exportCommand выгружает подписки в JSON или CSV
+func exportCommand(args []string) int {
+	fs, loader := newFlagSet("export")
+	format := fs.String("format", "json", "output format: json or csv")
+	output := fs.String("output", "", "output file (default stdout)")
+	userID := fs.String("user", "", "export only subscriptions of this user")
+	serviceName := fs.String("service", "", "export only subscriptions matching this service name")
+	cfg, ok := loadConfig(fs, loader, args)
+	if !ok {
+		return exitUsage
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "unsupported format %q, expected json or csv\n", *format)
+		return exitUsage
+	}
+	logger := newLogger(cfg, os.Stderr)
+
+	svc, closeDB, err := newService(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize service", "error", err)
+		return exitError
+	}
+	defer closeDB()
+
+	filters := models.SubscriptionFilters{}
+	if *userID != "" {
+		filters.UserIDs = []string{*userID}
+	}
+	if *serviceName != "" {
+		filters.ServiceNames = []string{*serviceName}
+	}
+
+	subscriptions, err := fetchAll(context.Background(), svc, filters)
+	if err != nil {
+		logger.Error("Failed to load subscriptions", "error", err)
+		return exitError
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			logger.Error("Failed to create output file", "error", err)
+			return exitError
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		err = writeCSV(w, subscriptions)
+	} else {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(subscriptions)
+	}
+	if err != nil {
+		logger.Error("Failed to write export", "error", err)
+		return exitError
+	}
+
+	logger.Info("Export finished", "count", len(subscriptions))
+	return exitOK
+}
+
+// fetchAll постранично читает все подписки, подходящие под фильтры
+func fetchAll(ctx context.Context, svc service.Service, filters models.SubscriptionFilters) ([]models.Subscription, error) {
+	const pageSize = 100
+
+	subscriptions := []models.Subscription{}
+	for offset := 0; ; offset += pageSize {
+		page := filters
+		page.Limit = pageSize
+		page.Offset = offset
+
+		resp, err := svc.GetSubscriptions(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, resp.Subscriptions...)
+
+		if len(resp.Subscriptions) < pageSize {
+			return subscriptions, nil
+		}
+	}
+}
+
+func writeCSV(w io.Writer, subscriptions []models.Subscription) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, s := range subscriptions {
+		endDate := ""
+		if s.EndDate != nil {
+			endDate = s.EndDate.String()
+		}
+		serviceID := ""
+		if s.ServiceID != nil {
+			serviceID = strconv.Itoa(*s.ServiceID)
+		}
+		record := []string{
+			strconv.Itoa(s.ID),
+			s.ServiceName,
+			serviceID,
+			strconv.Itoa(s.Price),
+			s.UserID,
+			s.StartDate.String(),
+			endDate,
+			string(s.Status),
+			strconv.FormatBool(s.AutoRenew),
+			string(s.BillingPeriod),
+			optional(s.Category),
+			strings.Join(s.Tags, csvTagSeparator),
+			optional(s.Notes),
+			s.CreatedAt.Format(time.RFC3339),
+			s.UpdatedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// optional возвращает значение строки или пустую строку для nil
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	category := "streaming"
+	notes := "family plan, shared"
+	serviceID := 3
+	end := dates.NewDate(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	subscriptions := []models.Subscription{
+		{
+			ID:            1,
+			ServiceName:   "Netflix",
+			ServiceID:     &serviceID,
+			Price:         499,
+			UserID:        "123e4567-e89b-12d3-a456-426614174000",
+			StartDate:     dates.NewDate(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+			EndDate:       &end,
+			Status:        models.StatusActive,
+			AutoRenew:     true,
+			BillingPeriod: models.BillingYearly,
+			Category:      &category,
+			Tags:          []string{"shared", "work"},
+			Notes:         &notes,
+		},
+		{
+			ID:            2,
+			ServiceName:   "Spotify",
+			Price:         299,
+			UserID:        "123e4567-e89b-12d3-a456-426614174000",
+			StartDate:     dates.NewDate(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			Status:        models.StatusPaused,
+			BillingPeriod: models.BillingMonthly,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, subscriptions); err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	if header != strings.Join(csvHeader, ",") {
+		t.Errorf("header = %q, want %q", header, strings.Join(csvHeader, ","))
+	}
+
+	requests, err := readImportCSV(&buf)
+	if err != nil {
+		t.Fatalf("readImportCSV() error = %v", err)
+	}
+
+	endDate := "2024-12-31"
+	want := []models.CreateSubscriptionRequest{
+		{
+			ServiceName:   "Netflix",
+			Price:         499,
+			UserID:        "123e4567-e89b-12d3-a456-426614174000",
+			StartDate:     "2024-01-15",
+			EndDate:       &endDate,
+			AutoRenew:     true,
+			BillingPeriod: models.BillingYearly,
+			Category:      &category,
+			Tags:          []string{"shared", "work"},
+			Notes:         &notes,
+		},
+		{
+			ServiceName:   "Spotify",
+			Price:         299,
+			UserID:        "123e4567-e89b-12d3-a456-426614174000",
+			StartDate:     "2024-02-01",
+			BillingPeriod: models.BillingMonthly,
+		},
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("readImportCSV() = %+v, want %+v", requests, want)
+	}
+}
+
+func TestReadImportCSVBaselineColumns(t *testing.T) {
+	input := "service_name,price,user_id,start_date,end_date\n" +
+		"Netflix,499,123e4567-e89b-12d3-a456-426614174000,01-2024,12-2024\n"
+
+	requests, err := readImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readImportCSV() error = %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if req.AutoRenew || req.BillingPeriod != "" || req.Category != nil || req.Tags != nil || req.Notes != nil {
+		t.Errorf("readImportCSV() = %+v, want only baseline fields", req)
+	}
+}
+
+func TestReadImportCSVInvalidAutoRenew(t *testing.T) {
+	input := "service_name,price,user_id,start_date,auto_renew\n" +
+		"Netflix,499,123e4567-e89b-12d3-a456-426614174000,01-2024,sometimes\n"
+
+	if _, err := readImportCSV(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "auto_renew") {
+		t.Errorf("readImportCSV() error = %v, want invalid auto_renew", err)
+	}
+}
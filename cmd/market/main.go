@@ -1,17 +1,103 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/IceMAN2377/market/app"
 	"github.com/IceMAN2377/market/internal/config"
+	"github.com/IceMAN2377/market/internal/logging"
+	"github.com/IceMAN2377/market/internal/repository/postgres"
+	"github.com/IceMAN2377/market/internal/service"
+	"github.com/IceMAN2377/market/internal/service/subscription"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"io"
 	"log/slog"
 	"os"
-	"os/signal"
 	"strings"
 )
 
+const usage = `Usage: market <command> [flags]
+
+Commands:
+  serve                          start the HTTP server (default)
+  migrate up [N]                 apply all or N pending migrations
+  migrate down [N]               roll back N migrations (default 1)
+  migrate goto <version>         migrate to the given version
+  migrate version                print the current schema version
+  migrate force <version>        set the version without running migrations
+  import [--allow-overlap] <file>
+                                 import subscriptions from a JSON or CSV file
+  export [--format json|csv]     export subscriptions
+  cost --from DATE --to DATE [--user UUID] [--service NAME] [--prorate]
+                                 calculate subscription cost for a period
+  config print                   print the effective configuration
+
+Dates are accepted as MM-YYYY, YYYY-MM or YYYY-MM-DD.
+Every command accepts --config and configuration flags (e.g. --postgres-host).
+`
+
+// Коды завершения
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
 func main() {
-	cfg := config.NewConfig()
+	args := os.Args[1:]
+
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
 
+	var code int
+	switch command {
+	case "serve":
+		code = serve(args)
+	case "migrate":
+		code = migrateCommand(args)
+	case "import":
+		code = importCommand(args)
+	case "export":
+		code = exportCommand(args)
+	case "cost":
+		code = costCommand(args)
+	case "config":
+		code = configCommand(args)
+	case "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		code = exitUsage
+	}
+
+	os.Exit(code)
+}
+
+// newFlagSet создает FlagSet подкоманды с общими флагами конфигурации
+func newFlagSet(name string) (*flag.FlagSet, *config.Loader) {
+	fs := flag.NewFlagSet("market "+name, flag.ContinueOnError)
+	return fs, config.NewLoader(fs)
+}
+
+// loadConfig разбирает аргументы подкоманды и загружает конфигурацию
+func loadConfig(fs *flag.FlagSet, loader *config.Loader, args []string) (*config.Config, bool) {
+	if err := fs.Parse(args); err != nil {
+		return nil, false
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	}
+
+	return cfg, true
+}
+
+// newLogger создает JSON логгер с уровнем из конфигурации
+func newLogger(cfg *config.Config, w io.Writer) *slog.Logger {
 	// Настройка логгера в зависимости от уровня
 	var logLevel slog.Level
 	switch strings.ToLower(cfg.LogLevel) {
@@ -27,26 +113,21 @@ func main() {
 		logLevel = slog.LevelInfo
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	return slog.New(logging.NewContextHandler(tracing.NewLogHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{
 		Level: logLevel,
-	}))
-
-	app := app.NewApp(cfg, logger)
-
-	go func() {
-		logger.Info("Starting subscription service",
-			slog.Int("port", cfg.HttpPort),
-			slog.String("log_level", cfg.LogLevel))
-
-		if err := app.Run(); err != nil {
-			logger.Error("Failed to start HTTP server", "error", err)
-			panic("failed to start http server: " + err.Error())
-		}
-	}()
+	}))))
+}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+// newService подключается к БД и собирает слой сервиса для подкоманд CLI.
+// Возвращаемая функция закрывает соединение с БД.
+func newService(cfg *config.Config, logger *slog.Logger) (service.Service, func(), error) {
+	db, err := app.OpenDB(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	<-quit
-	logger.Info("Shutting down subscription service")
+	repo := postgres.NewRepository(db, logger)
+	// Жесткие бюджеты проверяются только в API: импорт из CLI переносит
+	// существующие данные и не должен на них отклоняться
+	return subscription.NewService(repo, cfg.DateBounds(), nil), func() { db.Close() }, nil
 }
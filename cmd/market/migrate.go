@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/IceMAN2377/market/app"
+	"github.com/golang-migrate/migrate/v4"
+	"os"
+	"strconv"
+)
+
+// migrateCommand управляет схемой БД отдельно от запуска сервера
+func migrateCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: market migrate up|down|goto|version|force [flags] [arg]")
+		return exitUsage
+	}
+	action := args[0]
+
+	fs, loader := newFlagSet("migrate " + action)
+	cfg, ok := loadConfig(fs, loader, args[1:])
+	if !ok {
+		return exitUsage
+	}
+	logger := newLogger(cfg, os.Stderr)
+
+	// Числовой позиционный аргумент: количество шагов или версия
+	var (
+		n    int
+		hasN bool
+	)
+	if fs.NArg() > 0 {
+		v, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || v < 0 {
+			fmt.Fprintf(os.Stderr, "invalid argument %q: expected a non-negative number\n", fs.Arg(0))
+			return exitUsage
+		}
+		n, hasN = v, true
+	}
+
+	m, err := app.NewMigrator(cfg)
+	if err != nil {
+		logger.Error("Failed to initialize migrations", "error", err)
+		return exitError
+	}
+	defer m.Close()
+
+	switch action {
+	case "up":
+		if hasN {
+			err = m.Steps(n)
+		} else {
+			err = m.Up()
+		}
+	case "down":
+		if !hasN {
+			n = 1
+		}
+		err = m.Steps(-n)
+	case "goto":
+		if !hasN {
+			fmt.Fprintln(os.Stderr, "usage: market migrate goto [flags] <version>")
+			return exitUsage
+		}
+		err = m.Migrate(uint(n))
+	case "force":
+		if !hasN {
+			fmt.Fprintln(os.Stderr, "usage: market migrate force [flags] <version>")
+			return exitUsage
+		}
+		err = m.Force(n)
+	case "version":
+		version, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("no migrations applied")
+			return exitOK
+		}
+		if err != nil {
+			logger.Error("Failed to read migration version", "error", err)
+			return exitError
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate action %q\n", action)
+		return exitUsage
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		logger.Info("No migrations to apply")
+		return exitOK
+	}
+	if err != nil {
+		logger.Error("Migration failed", "action", action, "error", err)
+		return exitError
+	}
+
+	logger.Info("Migration completed", "action", action)
+	return exitOK
+}
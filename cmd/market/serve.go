@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/IceMAN2377/market/app"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// serve запускает HTTP сервер и блокируется до получения SIGINT/SIGTERM
+func serve(args []string) int {
+	fs, loader := newFlagSet("serve")
+	cfg, ok := loadConfig(fs, loader, args)
+	if !ok {
+		return exitUsage
+	}
+
+	logger := newLogger(cfg, os.Stdout)
+
+	app := app.NewApp(cfg, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Starting subscription service",
+			slog.Int("port", cfg.HttpPort),
+			slog.String("log_level", cfg.LogLevel))
+
+		serverErr <- app.Run()
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down subscription service")
+	case runErr = <-serverErr:
+		logger.Error("Failed to start HTTP server", "error", runErr)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to shutdown application", "error", err)
+		runErr = errors.Join(runErr, err)
+	}
+
+	logger.Info("Subscription service stopped")
+
+	if runErr != nil {
+		return exitError
+	}
+	return exitOK
+}
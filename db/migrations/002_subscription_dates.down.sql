@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_subscriptions_period;
+
+-- Точность до дня теряется: остается только месяц
+ALTER TABLE subscriptions
+    ALTER COLUMN start_date TYPE VARCHAR(7) USING to_char(start_date, 'MM-YYYY'),
+    ALTER COLUMN end_date TYPE VARCHAR(7) USING to_char(end_date, 'MM-YYYY');
+
+COMMENT ON COLUMN subscriptions.start_date IS 'Дата начала подписки в формате MM-YYYY';
+COMMENT ON COLUMN subscriptions.end_date IS 'Дата окончания подписки в формате MM-YYYY';
@@ -0,0 +1,10 @@
+-- Даты подписки хранятся с точностью до дня. Существующие значения MM-YYYY
+-- переводятся в первый день месяца для начала и последний день месяца для окончания.
+ALTER TABLE subscriptions
+    ALTER COLUMN start_date TYPE DATE USING to_date(start_date, 'MM-YYYY'),
+    ALTER COLUMN end_date TYPE DATE USING (to_date(end_date, 'MM-YYYY') + INTERVAL '1 month' - INTERVAL '1 day')::DATE;
+
+CREATE INDEX idx_subscriptions_period ON subscriptions(start_date, end_date);
+
+COMMENT ON COLUMN subscriptions.start_date IS 'Дата начала подписки';
+COMMENT ON COLUMN subscriptions.end_date IS 'Дата окончания подписки включительно (NULL — бессрочная)';
@@ -0,0 +1,32 @@
+-- Явный статус подписки вместо вывода из end_date
+ALTER TABLE subscriptions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'cancelled', 'expired')),
+    ADD COLUMN cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN cancelled_at TIMESTAMP;
+
+-- Подписки, срок которых уже истек
+UPDATE subscriptions SET status = 'expired' WHERE end_date < CURRENT_DATE;
+
+CREATE INDEX idx_subscriptions_status ON subscriptions(status);
+
+COMMENT ON COLUMN subscriptions.status IS 'Статус: active, paused, cancelled, expired';
+COMMENT ON COLUMN subscriptions.cancel_at_period_end IS 'Подписка будет отменена по окончании оплаченного месяца';
+COMMENT ON COLUMN subscriptions.cancelled_at IS 'Время отмены подписки';
+
+-- Периоды приостановки: дни с start_date по end_date не входят в стоимость
+CREATE TABLE subscription_pauses (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- У подписки может быть только одна незавершенная пауза
+CREATE UNIQUE INDEX idx_subscription_pauses_open ON subscription_pauses(subscription_id) WHERE end_date IS NULL;
+CREATE INDEX idx_subscription_pauses_subscription_id ON subscription_pauses(subscription_id);
+
+COMMENT ON TABLE subscription_pauses IS 'Периоды приостановки подписок';
+COMMENT ON COLUMN subscription_pauses.start_date IS 'Первый день паузы';
+COMMENT ON COLUMN subscription_pauses.end_date IS 'Последний день паузы включительно (NULL — пауза продолжается)';
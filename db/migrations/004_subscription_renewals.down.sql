@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS subscription_events;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS auto_renew;
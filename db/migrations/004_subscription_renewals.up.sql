@@ -0,0 +1,21 @@
+-- Автопродление: по достижении end_date подписка продлевается на следующий месяц
+ALTER TABLE subscriptions
+    ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN subscriptions.auto_renew IS 'Продлевать подписку по достижении даты окончания';
+
+-- Изменения, выполненные фоновыми задачами
+CREATE TABLE subscription_events (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    event VARCHAR(16) NOT NULL CHECK (event IN ('expired', 'cancelled', 'renewed')),
+    old_status VARCHAR(16) NOT NULL,
+    new_status VARCHAR(16) NOT NULL,
+    old_end_date DATE,
+    new_end_date DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_subscription_events_subscription_id ON subscription_events(subscription_id, created_at);
+
+COMMENT ON TABLE subscription_events IS 'Журнал автоматических изменений подписок (истечение, продление)';
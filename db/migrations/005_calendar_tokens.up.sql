@@ -0,0 +1,8 @@
+-- Секретные токены календарных подписок: хранится только SHA-256 токена
+CREATE TABLE calendar_tokens (
+    user_id UUID PRIMARY KEY,
+    token_hash BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE calendar_tokens IS 'Токены доступа к iCalendar-ленте продлений пользователя';
@@ -0,0 +1,27 @@
+-- Месячные бюджеты пользователя: на все подписки или на один сервис
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255),
+    monthly_limit INTEGER NOT NULL CHECK (monthly_limit > 0),
+    thresholds INTEGER[] NOT NULL,
+    hard BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Один бюджет на область: пользователь целиком или пользователь и сервис
+CREATE UNIQUE INDEX idx_budgets_scope ON budgets(user_id, COALESCE(service_name, ''));
+
+COMMENT ON COLUMN budgets.thresholds IS 'Пороги оповещений в процентах от лимита';
+COMMENT ON COLUMN budgets.hard IS 'Отклонять создание подписок, превышающих лимит';
+
+-- Отправленные оповещения: каждый порог срабатывает один раз за месяц
+CREATE TABLE budget_alerts (
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    threshold INTEGER NOT NULL,
+    spent INTEGER NOT NULL,
+    fired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (budget_id, month, threshold)
+);
@@ -0,0 +1,17 @@
+-- Запланированные изменения цены: учитываются в прогнозе расходов и
+-- применяются к подписке фоновой задачей в дату вступления в силу
+CREATE TABLE subscription_price_changes (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    price INTEGER NOT NULL CHECK (price > 0),
+    applied_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subscription_id, effective_date)
+);
+
+CREATE INDEX idx_subscription_price_changes_pending ON subscription_price_changes(effective_date)
+    WHERE applied_at IS NULL;
+
+COMMENT ON TABLE subscription_price_changes IS 'Запланированные изменения цены подписок';
+COMMENT ON COLUMN subscription_price_changes.applied_at IS 'Когда новая цена записана в подписку; NULL — еще не применено';
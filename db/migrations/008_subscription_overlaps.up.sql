@@ -0,0 +1,3 @@
+-- Поиск пересекающихся подписок пользователя на один сервис (название без учета регистра и пробелов по краям).
+-- Составной индекс idx_subscriptions_user_service из 001 остается для фильтрации по точному названию.
+CREATE INDEX idx_subscriptions_user_service_normalized ON subscriptions(user_id, lower(btrim(service_name)));
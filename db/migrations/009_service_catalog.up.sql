@@ -0,0 +1,29 @@
+-- Каталог сервисов: каноническое название, цена по умолчанию и категория
+CREATE TABLE services (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    default_price INTEGER CHECK (default_price > 0),
+    category VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Каноническое название и синонимы сервиса в нормализованном виде (нижний регистр,
+-- пробелы схлопнуты): по ним входящий service_name сопоставляется с каталогом
+CREATE TABLE service_aliases (
+    normalized VARCHAR(255) PRIMARY KEY,
+    alias VARCHAR(255) NOT NULL,
+    canonical BOOLEAN NOT NULL DEFAULT FALSE,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_service_aliases_service_id ON service_aliases(service_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN service_id INTEGER REFERENCES services(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_subscriptions_service_id ON subscriptions(service_id);
+
+COMMENT ON TABLE services IS 'Каталог сервисов';
+COMMENT ON TABLE service_aliases IS 'Названия сервисов каталога для сопоставления с service_name подписок';
+COMMENT ON COLUMN subscriptions.service_id IS 'Сервис каталога; NULL — название не найдено в каталоге';
@@ -0,0 +1,12 @@
+ALTER TABLE services ADD COLUMN category VARCHAR(64);
+
+UPDATE services s
+SET category = c.name
+FROM categories c
+WHERE c.id = s.category_id;
+
+ALTER TABLE services DROP COLUMN IF EXISTS category_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS subscription_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,48 @@
+-- Категории подписок и сервисов каталога; название уникально без учета регистра
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_categories_name ON categories(lower(name));
+
+-- Произвольные метки подписок; названия хранятся в нижнем регистре
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE
+);
+
+CREATE TABLE subscription_tags (
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+
+CREATE INDEX idx_subscription_tags_tag_id ON subscription_tags(tag_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_subscriptions_category_id ON subscriptions(category_id);
+
+-- Категория сервиса каталога переносится в общую таблицу категорий
+INSERT INTO categories (name)
+SELECT DISTINCT ON (lower(category)) category
+FROM services
+WHERE category IS NOT NULL
+ORDER BY lower(category), category;
+
+ALTER TABLE services
+    ADD COLUMN category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+UPDATE services s
+SET category_id = c.id
+FROM categories c
+WHERE lower(c.name) = lower(s.category);
+
+ALTER TABLE services DROP COLUMN category;
+
+COMMENT ON TABLE categories IS 'Категории подписок и сервисов';
+COMMENT ON TABLE tags IS 'Метки подписок';
+COMMENT ON TABLE subscription_tags IS 'Метки, назначенные подпискам';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tags_name_trgm;
+DROP INDEX IF EXISTS idx_subscriptions_notes_trgm;
+DROP INDEX IF EXISTS idx_subscriptions_service_name_trgm;
+DROP INDEX IF EXISTS idx_subscriptions_search_vector;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS notes;
+-- Расширение pg_trgm не удаляется: оно может использоваться вне приложения
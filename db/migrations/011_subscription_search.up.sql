@@ -0,0 +1,19 @@
+-- Триграммы для нечеткого поиска (опечатки, части слов)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE subscriptions ADD COLUMN notes TEXT;
+
+-- Полнотекстовый индекс: название сервиса весомее заметок. Конфигурация simple
+-- без стемминга, потому что названия и заметки бывают на разных языках.
+ALTER TABLE subscriptions ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', service_name), 'A') ||
+    setweight(to_tsvector('simple', coalesce(notes, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_subscriptions_search_vector ON subscriptions USING GIN (search_vector);
+CREATE INDEX idx_subscriptions_service_name_trgm ON subscriptions USING GIN (service_name gin_trgm_ops);
+CREATE INDEX idx_subscriptions_notes_trgm ON subscriptions USING GIN (notes gin_trgm_ops);
+CREATE INDEX idx_tags_name_trgm ON tags USING GIN (name gin_trgm_ops);
+
+COMMENT ON COLUMN subscriptions.notes IS 'Заметки пользователя о подписке';
+COMMENT ON COLUMN subscriptions.search_vector IS 'Полнотекстовый индекс названия сервиса и заметок';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_subscriptions_created_at;
+DROP INDEX IF EXISTS idx_subscriptions_end_date;
+DROP INDEX IF EXISTS idx_subscriptions_price;
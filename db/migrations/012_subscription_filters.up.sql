@@ -0,0 +1,6 @@
+-- Индексы для фильтров списка подписок по цене и датам. Дата начала уже
+-- покрыта idx_subscriptions_period, поиск по части названия — триграммным
+-- индексом idx_subscriptions_service_name_trgm.
+CREATE INDEX idx_subscriptions_price ON subscriptions(price);
+CREATE INDEX idx_subscriptions_end_date ON subscriptions(end_date);
+CREATE INDEX idx_subscriptions_created_at ON subscriptions(created_at);
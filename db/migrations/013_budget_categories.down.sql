@@ -0,0 +1,6 @@
+-- Бюджеты по категориям без категории совпали бы с бюджетами пользователя или сервиса
+DELETE FROM budgets WHERE category_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_budgets_scope;
+ALTER TABLE budgets DROP COLUMN IF EXISTS category_id;
+CREATE UNIQUE INDEX idx_budgets_scope ON budgets(user_id, COALESCE(service_name, ''));
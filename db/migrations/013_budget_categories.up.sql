@@ -0,0 +1,9 @@
+-- Бюджет может ограничивать подписки одной категории, отдельно или вместе с сервисом
+ALTER TABLE budgets
+    ADD COLUMN category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE;
+
+-- Один бюджет на область: пользователь, сервис и категория
+DROP INDEX IF EXISTS idx_budgets_scope;
+CREATE UNIQUE INDEX idx_budgets_scope ON budgets(user_id, COALESCE(service_name, ''), COALESCE(category_id, 0));
+
+COMMENT ON COLUMN budgets.category_id IS 'Категория подписок, на которые действует бюджет';
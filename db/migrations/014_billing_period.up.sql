@@ -0,0 +1,6 @@
+-- Периодичность списаний; цена подписки остается месячной
+ALTER TABLE subscriptions
+    ADD COLUMN billing_period VARCHAR(16) NOT NULL DEFAULT 'monthly'
+        CHECK (billing_period IN ('monthly', 'quarterly', 'yearly'));
+
+COMMENT ON COLUMN subscriptions.billing_period IS 'Периодичность списаний: monthly, quarterly или yearly';
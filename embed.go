@@ -0,0 +1,26 @@
+// Package market содержит ресурсы, встроенные в бинарный файл сервиса
+package market
+
+import "embed"
+
+// Migrations SQL-миграции схемы БД (каталог db/migrations)
+//
+//go:embed db/migrations/*.sql
+var Migrations embed.FS
+
+// MigrationsDir путь к миграциям внутри Migrations
+const MigrationsDir = "db/migrations"
+
+// SwaggerSpec спецификация OpenAPI в формате YAML
+//
+//go:embed swagger.yaml
+var SwaggerSpec []byte
+
+// Locales каталоги сообщений для локализации ответов (каталог locales).
+// Язык определяется именем файла: en.json, ru.json.
+//
+//go:embed locales/*.json
+var Locales embed.FS
+
+// LocalesDir путь к каталогам сообщений внутри Locales
+const LocalesDir = "locales"
@@ -1,37 +1,315 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/IceMAN2377/market/internal/dates"
 	"github.com/caarlos0/env"
+	"gopkg.in/yaml.v3"
 )
 
+// Значение, которым заменяются секреты при выводе конфигурации
+const redacted = "******"
+
+// Config параметры приложения. Источники применяются по порядку, каждый
+// следующий переопределяет предыдущий: значения по умолчанию (тег default),
+// файл конфигурации (--config), переменные окружения, флаги командной строки.
+// Имя флага выводится из имени переменной окружения: HTTP_PORT -> --http-port.
 type Config struct {
 	// База данных
-	PostgresMigrate  bool   `env:"POSTGRES_MIGRATE" envDefault:"true"`
-	PostgresHost     string `env:"POSTGRES_HOST,required"`
-	PostgresPort     int    `env:"POSTGRES_PORT,required"`
-	PostgresUser     string `env:"POSTGRES_USER,required"`
-	PostgresPassword string `env:"POSTGRES_PASSWORD,required"`
-	PostgresDb       string `env:"POSTGRES_DB,required"`
-	PostgresSslMode  string `env:"POSTGRES_SSL_MODE" envDefault:"disable"`
+	PostgresMigrate      bool   `env:"POSTGRES_MIGRATE" yaml:"postgres_migrate" toml:"postgres_migrate" default:"false"` // в проде миграции запускаются отдельно: market migrate up
+	PostgresHost         string `env:"POSTGRES_HOST" yaml:"postgres_host" toml:"postgres_host"`
+	PostgresPort         int    `env:"POSTGRES_PORT" yaml:"postgres_port" toml:"postgres_port" default:"5432"`
+	PostgresUser         string `env:"POSTGRES_USER" yaml:"postgres_user" toml:"postgres_user"`
+	PostgresPassword     string `env:"POSTGRES_PASSWORD" yaml:"postgres_password" toml:"postgres_password" secret:"true"`
+	PostgresPasswordFile string `env:"POSTGRES_PASSWORD_FILE" yaml:"postgres_password_file" toml:"postgres_password_file"`
+	PostgresDb           string `env:"POSTGRES_DB" yaml:"postgres_db" toml:"postgres_db"`
+	PostgresSslMode      string `env:"POSTGRES_SSL_MODE" yaml:"postgres_ssl_mode" toml:"postgres_ssl_mode" default:"disable"`
+
+	// Внешние ресурсы вместо встроенных в бинарник (для разработки)
+	MigrationsPath string `env:"MIGRATIONS_PATH" yaml:"migrations_path" toml:"migrations_path"`
+	SwaggerPath    string `env:"SWAGGER_PATH" yaml:"swagger_path" toml:"swagger_path"`
+	LocalesPath    string `env:"LOCALES_PATH" yaml:"locales_path" toml:"locales_path"`
 
 	// HTTP сервер
-	HttpPort int `env:"HTTP_PORT" envDefault:"8080"`
+	HttpPort              int           `env:"HTTP_PORT" yaml:"http_port" toml:"http_port" default:"8080"`
+	HttpReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" yaml:"http_read_timeout" toml:"http_read_timeout" default:"15s"`
+	HttpReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" yaml:"http_read_header_timeout" toml:"http_read_header_timeout" default:"5s"`
+	HttpWriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" yaml:"http_write_timeout" toml:"http_write_timeout" default:"30s"`
+	HttpIdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" yaml:"http_idle_timeout" toml:"http_idle_timeout" default:"120s"`
+	HttpRequestTimeout    time.Duration `env:"HTTP_REQUEST_TIMEOUT" yaml:"http_request_timeout" toml:"http_request_timeout" default:"10s"` // дедлайн контекста запроса, включая SQL
+	HttpMaxBodyBytes      int64         `env:"HTTP_MAX_BODY_BYTES" yaml:"http_max_body_bytes" toml:"http_max_body_bytes" default:"1048576"`
+
+	// Проверка запросов и ответов по спецификации OpenAPI
+	OpenAPIValidateRequests  bool `env:"OPENAPI_VALIDATE_REQUESTS" yaml:"openapi_validate_requests" toml:"openapi_validate_requests" default:"true"`
+	OpenAPIValidateResponses bool `env:"OPENAPI_VALIDATE_RESPONSES" yaml:"openapi_validate_responses" toml:"openapi_validate_responses" default:"false"` // строгий режим для тестов
+
+	// Корректная остановка
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" yaml:"shutdown_timeout" toml:"shutdown_timeout" default:"15s"`
+	// Пауза между снятием готовности и закрытием listener'а; должна быть больше
+	// периода проверки /readyz балансировщиком, иначе он успеет прислать запросы
+	// в закрытый listener
+	ShutdownDelay time.Duration `env:"SHUTDOWN_DELAY" yaml:"shutdown_delay" toml:"shutdown_delay" default:"5s"`
+
+	// Проверки состояния
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" yaml:"health_check_timeout" toml:"health_check_timeout" default:"2s"`
+
+	// Допустимый диапазон лет во входных датах: от DATE_MIN_YEAR до текущего года плюс DATE_MAX_YEARS_AHEAD
+	DateMinYear       int `env:"DATE_MIN_YEAR" yaml:"date_min_year" toml:"date_min_year" default:"2000"`
+	DateMaxYearsAhead int `env:"DATE_MAX_YEARS_AHEAD" yaml:"date_max_years_ahead" toml:"date_max_years_ahead" default:"10"`
+
+	// Фоновые задачи. В нескольких репликах каждую задачу выполняет одна из них
+	// (блокировка pg_try_advisory_lock), остальные пропускают запуск.
+	SchedulerEnabled    bool          `env:"SCHEDULER_ENABLED" yaml:"scheduler_enabled" toml:"scheduler_enabled" default:"true"`
+	SchedulerJobTimeout time.Duration `env:"SCHEDULER_JOB_TIMEOUT" yaml:"scheduler_job_timeout" toml:"scheduler_job_timeout" default:"5m"` // дедлайн одного запуска задачи
+	JobExpireInterval   time.Duration `env:"JOB_EXPIRE_INTERVAL" yaml:"job_expire_interval" toml:"job_expire_interval" default:"1h"`
+	JobRenewInterval    time.Duration `env:"JOB_RENEW_INTERVAL" yaml:"job_renew_interval" toml:"job_renew_interval" default:"1h"`
+	JobBudgetInterval   time.Duration `env:"JOB_BUDGET_INTERVAL" yaml:"job_budget_interval" toml:"job_budget_interval" default:"15m"`
+	JobPriceInterval    time.Duration `env:"JOB_PRICE_INTERVAL" yaml:"job_price_interval" toml:"job_price_interval" default:"1h"`
+
+	// Бюджеты и оповещения
+	BudgetThresholds     []int         `env:"BUDGET_THRESHOLDS" yaml:"budget_thresholds" toml:"budget_thresholds" default:"80,100"`          // пороги по умолчанию, % от лимита
+	Notifier             string        `env:"NOTIFIER" yaml:"notifier" toml:"notifier" default:"log"`                                        // log, webhook
+	NotifyWebhookURL     string        `env:"NOTIFY_WEBHOOK_URL" yaml:"notify_webhook_url" toml:"notify_webhook_url" secret:"true"`          // может содержать токен
+	NotifyWebhookSecret  string        `env:"NOTIFY_WEBHOOK_SECRET" yaml:"notify_webhook_secret" toml:"notify_webhook_secret" secret:"true"` // ключ подписи HMAC-SHA256
+	NotifyWebhookTimeout time.Duration `env:"NOTIFY_WEBHOOK_TIMEOUT" yaml:"notify_webhook_timeout" toml:"notify_webhook_timeout" default:"5s"`
+
+	// Язык ответов, если Accept-Language не задан или не поддерживается
+	DefaultLanguage string `env:"DEFAULT_LANGUAGE" yaml:"default_language" toml:"default_language" default:"en"`
 
 	// Логирование
-	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+	LogLevel string `env:"LOG_LEVEL" yaml:"log_level" toml:"log_level" default:"info"`
+
+	// Трассировка (OpenTelemetry)
+	ServiceName         string  `env:"SERVICE_NAME" yaml:"service_name" toml:"service_name" default:"market"`
+	TracingExporter     string  `env:"TRACING_EXPORTER" yaml:"tracing_exporter" toml:"tracing_exporter" default:"none"` // none, otlp, stdout, file
+	TracingOTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" yaml:"tracing_otlp_endpoint" toml:"tracing_otlp_endpoint" default:"localhost:4318"`
+	TracingOTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" yaml:"tracing_otlp_insecure" toml:"tracing_otlp_insecure" default:"true"`
+	TracingFile         string  `env:"TRACING_FILE" yaml:"tracing_file" toml:"tracing_file" default:"traces.json"`
+	TracingSampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" yaml:"tracing_sample_ratio" toml:"tracing_sample_ratio" default:"1"`
+}
+
+// Loader регистрирует флаги конфигурации в FlagSet подкоманды, чтобы
+// подкоманды могли объявлять собственные флаги рядом с общими
+type Loader struct {
+	configPath *string
+	flagValues []flagValue
+}
+
+type flagValue struct {
+	name  string
+	index int
+	value string
 }
 
-// NewConfig creates a new Config
-func NewConfig() *Config {
+// NewLoader регистрирует в fs флаг --config и по флагу на каждый параметр
+func NewLoader(fs *flag.FlagSet) *Loader {
+	l := &Loader{
+		configPath: fs.String("config", os.Getenv("CONFIG_FILE"), "path to YAML or TOML configuration file"),
+	}
+
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		envName := t.Field(i).Tag.Get("env")
+		name := flagName(envName)
+
+		// Логические флаги можно указать без значения: --postgres-migrate
+		register := fs.Func
+		if t.Field(i).Type.Kind() == reflect.Bool {
+			register = fs.BoolFunc
+		}
+		register(name, "overrides "+envName, func(value string) error {
+			// Флаги разбираются первыми, чтобы узнать путь к файлу конфигурации,
+			// но применяются последними
+			l.flagValues = append(l.flagValues, flagValue{name: name, index: i, value: value})
+			return nil
+		})
+	}
+
+	return l
+}
 
+// Load собирает конфигурацию после разбора FlagSet и проверяет ее
+func (l *Loader) Load() (*Config, error) {
 	cfg := &Config{}
+
+	if err := cfg.readDefaults(); err != nil {
+		return nil, err
+	}
+
+	if *l.configPath != "" {
+		if err := cfg.readFromFile(*l.configPath); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := cfg.readFromEnvironment(); err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	v := reflect.ValueOf(cfg).Elem()
+	for _, fv := range l.flagValues {
+		if err := setField(v.Field(fv.index), fv.value); err != nil {
+			return nil, fmt.Errorf("invalid value %q for flag --%s: %w", fv.value, fv.name, err)
+		}
+	}
+
+	if err := cfg.readSecretFiles(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
-	return cfg
+
+	return cfg, nil
+}
+
+// readDefaults заполняет поля значениями из тега default
+func (c *Config) readDefaults() error {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		def, ok := v.Type().Field(i).Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), def); err != nil {
+			return fmt.Errorf("invalid default for %s: %w", v.Type().Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// readFromFile читает настройки из файла YAML или TOML
+func (c *Config) readFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
 }
 
-// readFromEnvironment reads the settings from environment variables.
+// readFromEnvironment читает настройки из переменных окружения.
+// Списки разбираются так же, как во флагах и default: элементы
+// обрезаются от пробелов, поэтому BUDGET_THRESHOLDS="80, 100" допустим
 func (c *Config) readFromEnvironment() error {
-	return env.Parse(c)
+	return env.ParseWithFuncs(c, env.CustomParsers{
+		reflect.TypeOf([]int{}): func(value string) (interface{}, error) {
+			var list []int
+			err := setField(reflect.ValueOf(&list).Elem(), value)
+			return list, err
+		},
+	})
+}
+
+// readSecretFiles подставляет секреты из файлов (например, Docker/Kubernetes secrets)
+func (c *Config) readSecretFiles() error {
+	if c.PostgresPasswordFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.PostgresPasswordFile)
+	if err != nil {
+		return fmt.Errorf("failed to read postgres password file: %w", err)
+	}
+	c.PostgresPassword = strings.TrimRight(string(data), "\r\n")
+
+	return nil
+}
+
+// Redacted возвращает копию конфигурации со скрытыми секретами
+func (c *Config) Redacted() *Config {
+	cp := *c
+	v := reflect.ValueOf(&cp).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("secret") == "true" && v.Field(i).String() != "" {
+			v.Field(i).SetString(redacted)
+		}
+	}
+	return &cp
+}
+
+// YAML возвращает конфигурацию в формате YAML со скрытыми секретами
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
+
+// DateBounds возвращает допустимый диапазон лет во входных датах
+func (c *Config) DateBounds() dates.Bounds {
+	return dates.Bounds{MinYear: c.DateMinYear, MaxYearsAhead: c.DateMaxYearsAhead}
+}
+
+// flagName преобразует имя переменной окружения в имя флага
+func flagName(envName string) string {
+	return strings.ReplaceAll(strings.ToLower(envName), "_", "-")
+}
+
+// setField присваивает полю значение, разобранное из строки
+func setField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		// Список через запятую, как в переменных окружения
+		parts := strings.Split(value, ",")
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setField(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return errors.New("unsupported field type " + field.Type().String())
+	}
+	return nil
 }
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setRequiredEnv задает обязательные настройки подключения к PostgreSQL
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("POSTGRES_HOST", "localhost")
+	t.Setenv("POSTGRES_USER", "market")
+	t.Setenv("POSTGRES_PASSWORD", "secret")
+	t.Setenv("POSTGRES_DB", "market")
+}
+
+func TestLoaderFlags(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SCHEDULER_ENABLED", "true")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	loader := NewLoader(fs)
+
+	args := []string{"--postgres-migrate", "--scheduler-enabled=false", "--http-port", "9090", "serve"}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "serve" {
+		t.Errorf("args = %v, want [serve]", fs.Args())
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.PostgresMigrate {
+		t.Error("PostgresMigrate = false, want true from a flag without value")
+	}
+	if cfg.SchedulerEnabled {
+		t.Error("SchedulerEnabled = true, want false from the flag over the environment")
+	}
+	if cfg.HttpPort != 9090 {
+		t.Errorf("HttpPort = %d, want 9090", cfg.HttpPort)
+	}
+	if cfg.ShutdownDelay != 5*time.Second {
+		t.Errorf("ShutdownDelay = %s, want default 5s", cfg.ShutdownDelay)
+	}
+}
+
+func TestLoaderInvalidBoolFlag(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	loader := NewLoader(fs)
+
+	if err := fs.Parse([]string{"--postgres-migrate=maybe"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := loader.Load(); err == nil || !strings.Contains(err.Error(), "--postgres-migrate") {
+		t.Errorf("Load() error = %v, want invalid value for --postgres-migrate", err)
+	}
+}
+
+func TestLoaderBudgetThresholds(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []int
+	}{
+		{"without spaces", "80,100", []int{80, 100}},
+		{"spaces after comma", "80, 100", []int{80, 100}},
+		{"spaces around elements", " 50 , 90 ,100 ", []int{50, 90, 100}},
+		{"single value", "100", []int{100}},
+	}
+
+	for _, tt := range tests {
+		for _, source := range []string{"env", "flag"} {
+			t.Run(tt.name+"/"+source, func(t *testing.T) {
+				setRequiredEnv(t)
+				t.Setenv("BUDGET_THRESHOLDS", "")
+				os.Unsetenv("BUDGET_THRESHOLDS")
+
+				fs := flag.NewFlagSet("test", flag.ContinueOnError)
+				fs.SetOutput(io.Discard)
+				loader := NewLoader(fs)
+
+				var args []string
+				if source == "env" {
+					t.Setenv("BUDGET_THRESHOLDS", tt.value)
+				} else {
+					args = []string{"--budget-thresholds", tt.value}
+				}
+				if err := fs.Parse(args); err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				cfg, err := loader.Load()
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if !slices.Equal(cfg.BudgetThresholds, tt.want) {
+					t.Errorf("BudgetThresholds = %v, want %v", cfg.BudgetThresholds, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestRedactedWebhookURL(t *testing.T) {
+	cfg := &Config{NotifyWebhookURL: "https://hooks.example.com/services/T000/B000/token"}
+
+	data, err := cfg.YAML()
+	if err != nil {
+		t.Fatalf("YAML() error = %v", err)
+	}
+	if strings.Contains(string(data), "token") {
+		t.Errorf("YAML() contains the webhook URL:\n%s", data)
+	}
+	if cfg.NotifyWebhookURL == redacted {
+		t.Error("YAML() redacted the original config")
+	}
+}
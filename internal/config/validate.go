@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	sslModes        = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logLevels       = []string{"debug", "info", "warn", "error"}
+	tracingExporter = []string{"none", "otlp", "stdout", "file"}
+	notifiers       = []string{"log", "webhook"}
+)
+
+// FieldError описывает некорректное значение одного параметра
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError содержит все найденные ошибки конфигурации
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, fe := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(fe.Field)
+		b.WriteString(": ")
+		b.WriteString(fe.Message)
+	}
+	return b.String()
+}
+
+// Validate проверяет конфигурацию и возвращает сразу все найденные ошибки
+func (c *Config) Validate() error {
+	v := &ValidationError{}
+
+	required := map[string]string{
+		"POSTGRES_HOST": c.PostgresHost,
+		"POSTGRES_USER": c.PostgresUser,
+		"POSTGRES_DB":   c.PostgresDb,
+	}
+	for _, name := range []string{"POSTGRES_HOST", "POSTGRES_USER", "POSTGRES_DB"} {
+		if strings.TrimSpace(required[name]) == "" {
+			v.add(name, "is required")
+		}
+	}
+	if c.PostgresPassword == "" {
+		v.add("POSTGRES_PASSWORD", "is required (or set POSTGRES_PASSWORD_FILE)")
+	}
+
+	v.port("POSTGRES_PORT", c.PostgresPort)
+	v.port("HTTP_PORT", c.HttpPort)
+	v.oneOf("POSTGRES_SSL_MODE", c.PostgresSslMode, sslModes)
+	v.oneOf("LOG_LEVEL", strings.ToLower(c.LogLevel), logLevels)
+	v.oneOf("TRACING_EXPORTER", strings.ToLower(c.TracingExporter), tracingExporter)
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		v.add("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+	if c.HttpMaxBodyBytes < 0 {
+		v.add("HTTP_MAX_BODY_BYTES", "must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		v.add("SHUTDOWN_TIMEOUT", "must be positive")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		v.add("HEALTH_CHECK_TIMEOUT", "must be positive")
+	}
+	if c.DateMinYear < 1 {
+		v.add("DATE_MIN_YEAR", "must be positive")
+	}
+	if c.DateMaxYearsAhead < 0 {
+		v.add("DATE_MAX_YEARS_AHEAD", "must not be negative")
+	}
+	if strings.TrimSpace(c.DefaultLanguage) == "" {
+		v.add("DEFAULT_LANGUAGE", "is required")
+	}
+	if c.SchedulerEnabled {
+		v.positive("SCHEDULER_JOB_TIMEOUT", c.SchedulerJobTimeout)
+		v.positive("JOB_EXPIRE_INTERVAL", c.JobExpireInterval)
+		v.positive("JOB_RENEW_INTERVAL", c.JobRenewInterval)
+		v.positive("JOB_BUDGET_INTERVAL", c.JobBudgetInterval)
+		v.positive("JOB_PRICE_INTERVAL", c.JobPriceInterval)
+	}
+	if len(c.BudgetThresholds) == 0 {
+		v.add("BUDGET_THRESHOLDS", "is required")
+	}
+	for _, t := range c.BudgetThresholds {
+		if t < 1 || t > 1000 {
+			v.add("BUDGET_THRESHOLDS", fmt.Sprintf("must be between 1 and 1000 percent, got %d", t))
+			break
+		}
+	}
+	v.oneOf("NOTIFIER", strings.ToLower(c.Notifier), notifiers)
+	if strings.ToLower(c.Notifier) == "webhook" {
+		if strings.TrimSpace(c.NotifyWebhookURL) == "" {
+			v.add("NOTIFY_WEBHOOK_URL", "is required when NOTIFIER is webhook")
+		}
+		v.positive("NOTIFY_WEBHOOK_TIMEOUT", c.NotifyWebhookTimeout)
+	}
+
+	if len(v.Errors) > 0 {
+		return v
+	}
+	return nil
+}
+
+func (v *ValidationError) add(field, message string) {
+	v.Errors = append(v.Errors, FieldError{Field: field, Message: message})
+}
+
+func (v *ValidationError) positive(field string, d time.Duration) {
+	if d <= 0 {
+		v.add(field, "must be positive")
+	}
+}
+
+func (v *ValidationError) port(field string, port int) {
+	if port < 1 || port > 65535 {
+		v.add(field, fmt.Sprintf("must be between 1 and 65535, got %d", port))
+	}
+}
+
+func (v *ValidationError) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), value))
+}
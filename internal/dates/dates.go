@@ -0,0 +1,226 @@
+// Package dates разбирает даты подписок в нескольких форматах и
+// форматирует их в ответах в формате, выбранном клиентом
+package dates
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout формат дат в ответах API
+type Layout string
+
+const (
+	LayoutISO       Layout = "iso"        // 2025-03-15
+	LayoutYearMonth Layout = "year-month" // 2025-03
+	LayoutMonthYear Layout = "month-year" // 03-2025
+)
+
+// Layouts допустимые значения формата в порядке документации
+var Layouts = []Layout{LayoutISO, LayoutYearMonth, LayoutMonthYear}
+
+var goLayouts = map[Layout]string{
+	LayoutISO:       "2006-01-02",
+	LayoutYearMonth: "2006-01",
+	LayoutMonthYear: "01-2006",
+}
+
+// ErrUnknownLayout неизвестное имя формата дат
+var ErrUnknownLayout = errors.New("unknown date layout")
+
+// ParseLayout разбирает имя формата; пустая строка означает ISO
+func ParseLayout(name string) (Layout, error) {
+	if name == "" {
+		return LayoutISO, nil
+	}
+	if _, ok := goLayouts[Layout(name)]; !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownLayout, name)
+	}
+	return Layout(name), nil
+}
+
+// Precision точность введенной даты
+type Precision int
+
+const (
+	PrecisionMonth Precision = iota // MM-YYYY или YYYY-MM
+	PrecisionDay                    // YYYY-MM-DD
+)
+
+// ErrInvalidDate дата не соответствует ни одному из поддерживаемых форматов
+var ErrInvalidDate = errors.New("invalid date")
+
+// Parse разбирает дату в формате MM-YYYY, YYYY-MM или YYYY-MM-DD.
+// Для форматов без дня возвращается первое число месяца.
+func Parse(s string) (time.Time, Precision, error) {
+	for _, input := range []struct {
+		layout    string
+		precision Precision
+	}{
+		{"2006-01-02", PrecisionDay},
+		{"2006-01", PrecisionMonth},
+		{"01-2006", PrecisionMonth},
+	} {
+		if len(s) != len(input.layout) {
+			continue
+		}
+		if t, err := time.Parse(input.layout, s); err == nil {
+			return t, input.precision, nil
+		}
+	}
+	return time.Time{}, 0, fmt.Errorf("%w %q: expected MM-YYYY, YYYY-MM or YYYY-MM-DD", ErrInvalidDate, s)
+}
+
+// ParseStart разбирает дату начала периода: для месяца — его первый день
+func ParseStart(s string) (time.Time, error) {
+	t, _, err := Parse(s)
+	return t, err
+}
+
+// ParseEnd разбирает дату окончания периода (включительно): для месяца — его последний день
+func ParseEnd(s string) (time.Time, error) {
+	t, precision, err := Parse(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if precision == PrecisionMonth {
+		t = EndOfMonth(t)
+	}
+	return t, nil
+}
+
+// StartOfMonth возвращает первый день месяца даты t
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EndOfMonth возвращает последний день месяца даты t
+func EndOfMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, 1, -1)
+}
+
+// DaysInMonth возвращает число дней в месяце даты t
+func DaysInMonth(t time.Time) int {
+	return EndOfMonth(t).Day()
+}
+
+// AddMonths сдвигает дату на n месяцев. Последний день месяца остается
+// последним днем целевого месяца, как и день, которого в нем нет (31 -> 30).
+func AddMonths(t time.Time, n int) time.Time {
+	target := StartOfMonth(t).AddDate(0, n, 0)
+	if t.Day() == DaysInMonth(t) || t.Day() > DaysInMonth(target) {
+		return EndOfMonth(target)
+	}
+	return time.Date(target.Year(), target.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MaxWindowDays наибольшая длина окна, принимаемая ParseWindow
+const MaxWindowDays = 366
+
+// ErrInvalidWindow окно задано не в формате <N>d или <N>w либо длиннее MaxWindowDays
+var ErrInvalidWindow = errors.New("invalid window")
+
+// ParseWindow разбирает длину окна в днях (30d) или неделях (4w)
+// и возвращает число дней
+func ParseWindow(s string) (int, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidWindow, s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 1 || strings.ContainsAny(s[:len(s)-1], "+-") {
+		return 0, fmt.Errorf("%w %q", ErrInvalidWindow, s)
+	}
+
+	switch s[len(s)-1] {
+	case 'd':
+	case 'w':
+		n *= 7
+	default:
+		return 0, fmt.Errorf("%w %q", ErrInvalidWindow, s)
+	}
+	if n > MaxWindowDays {
+		return 0, fmt.Errorf("%w %q", ErrInvalidWindow, s)
+	}
+	return n, nil
+}
+
+// Bounds допустимый диапазон лет во входных датах
+type Bounds struct {
+	MinYear       int
+	MaxYearsAhead int // максимальный год — текущий плюс MaxYearsAhead
+}
+
+// MaxYear возвращает максимальный допустимый год
+func (b Bounds) MaxYear() int {
+	return time.Now().Year() + b.MaxYearsAhead
+}
+
+// Contains сообщает, попадает ли год даты в допустимый диапазон
+func (b Bounds) Contains(t time.Time) bool {
+	return t.Year() >= b.MinYear && t.Year() <= b.MaxYear()
+}
+
+// Date календарная дата без времени. В JSON выводится в формате Layout
+// (по умолчанию ISO), в БД хранится как DATE.
+type Date struct {
+	time.Time
+	layout Layout
+}
+
+// NewDate создает дату из time.Time, отбрасывая время суток
+func NewDate(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// In возвращает дату, которая выводится в формате layout
+func (d Date) In(layout Layout) Date {
+	d.layout = layout
+	return d
+}
+
+// String форматирует дату в выбранном формате
+func (d Date) String() string {
+	layout, ok := goLayouts[d.layout]
+	if !ok {
+		layout = goLayouts[LayoutISO]
+	}
+	return d.Time.Format(layout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := ParseStart(s)
+	if err != nil {
+		return err
+	}
+	*d = NewDate(t)
+	return nil
+}
+
+// Scan читает значение колонки DATE
+func (d *Date) Scan(value any) error {
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into dates.Date", value)
+	}
+	*d = NewDate(t)
+	return nil
+}
+
+// Value записывает дату в колонку DATE
+func (d Date) Value() (driver.Value, error) {
+	return d.Time, nil
+}
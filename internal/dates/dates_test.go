@@ -0,0 +1,112 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseStartEnd(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"2024-02", day(2024, 2, 1), day(2024, 2, 29)},
+		{"02-2023", day(2023, 2, 1), day(2023, 2, 28)},
+		{"2024-12", day(2024, 12, 1), day(2024, 12, 31)},
+		{"04-2024", day(2024, 4, 1), day(2024, 4, 30)},
+		{"2024-02-15", day(2024, 2, 15), day(2024, 2, 15)},
+		{"2024-01-31", day(2024, 1, 31), day(2024, 1, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, err := ParseStart(tt.input)
+			if err != nil || !start.Equal(tt.start) {
+				t.Errorf("ParseStart() = %v, %v; want %v", start, err, tt.start)
+			}
+			end, err := ParseEnd(tt.input)
+			if err != nil || !end.Equal(tt.end) {
+				t.Errorf("ParseEnd() = %v, %v; want %v", end, err, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "2024", "2024-13", "13-2024", "2024-2", "2023-02-29", "2024/02", "2024-02-1"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseStart(input); err == nil {
+				t.Errorf("ParseStart(%q) succeeded, want error", input)
+			}
+			if _, err := ParseEnd(input); err == nil {
+				t.Errorf("ParseEnd(%q) succeeded, want error", input)
+			}
+		})
+	}
+}
+
+func TestMonthBoundaries(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		start time.Time
+		end   time.Time
+		days  int
+	}{
+		{day(2024, 2, 10), day(2024, 2, 1), day(2024, 2, 29), 29},
+		{day(2023, 2, 28), day(2023, 2, 1), day(2023, 2, 28), 28},
+		{day(2024, 12, 31), day(2024, 12, 1), day(2024, 12, 31), 31},
+		{day(2024, 4, 1), day(2024, 4, 1), day(2024, 4, 30), 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date.Format("2006-01-02"), func(t *testing.T) {
+			if got := StartOfMonth(tt.date); !got.Equal(tt.start) {
+				t.Errorf("StartOfMonth() = %v, want %v", got, tt.start)
+			}
+			if got := EndOfMonth(tt.date); !got.Equal(tt.end) {
+				t.Errorf("EndOfMonth() = %v, want %v", got, tt.end)
+			}
+			if got := DaysInMonth(tt.date); got != tt.days {
+				t.Errorf("DaysInMonth() = %d, want %d", got, tt.days)
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		name   string
+		date   time.Time
+		months int
+		want   time.Time
+	}{
+		{"mid month", day(2024, 1, 15), 1, day(2024, 2, 15)},
+		{"31st to 30-day month", day(2024, 3, 31), 1, day(2024, 4, 30)},
+		{"31st to leap february", day(2024, 1, 31), 1, day(2024, 2, 29)},
+		{"31st to february", day(2023, 1, 31), 1, day(2023, 2, 28)},
+		{"30th to leap february", day(2024, 1, 30), 1, day(2024, 2, 29)},
+		{"30th keeps day after february", day(2024, 1, 30), 2, day(2024, 3, 30)},
+		{"last day stays last day", day(2024, 4, 30), 1, day(2024, 5, 31)},
+		{"february 29 to next month", day(2024, 2, 29), 1, day(2024, 3, 31)},
+		{"february 29 to next year", day(2024, 2, 29), 12, day(2025, 2, 28)},
+		{"february 29 to next leap year", day(2024, 2, 29), 48, day(2028, 2, 29)},
+		{"february 28 to leap february", day(2023, 2, 28), 12, day(2024, 2, 29)},
+		{"29th across february", day(2023, 1, 29), 1, day(2023, 2, 28)},
+		{"quarter", day(2024, 11, 30), 3, day(2025, 2, 28)},
+		{"year boundary", day(2024, 12, 15), 1, day(2025, 1, 15)},
+		{"zero", day(2024, 5, 31), 0, day(2024, 5, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddMonths(tt.date, tt.months); !got.Equal(tt.want) {
+				t.Errorf("AddMonths(%s, %d) = %s, want %s", tt.date.Format(time.DateOnly), tt.months,
+					got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
@@ -1,19 +1,46 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 var (
 	// Основные ошибки бизнес-логики
-	ErrNotFound      = errors.New("subscription not found")
-	ErrAlreadyExists = errors.New("subscription already exists")
-	ErrInvalidData   = errors.New("invalid data provided")
+	ErrNotFound         = errors.New("subscription not found")
+	ErrAlreadyExists    = errors.New("subscription already exists")
+	ErrInvalidData      = errors.New("invalid data provided")
+	ErrInvalidID        = errors.New("invalid subscription ID")
+	ErrNoFieldsToUpdate = errors.New("at least one field must be provided for update")
+
+	// Ошибки жизненного цикла подписки
+	ErrInvalidStatusTransition = errors.New("invalid subscription status transition")
+
+	// Ошибки запланированных изменений цены
+	ErrPriceChangeNotFound      = errors.New("pending price change not found")
+	ErrPriceChangeAlreadyExists = errors.New("a price change for this date is already scheduled")
+
+	// Ошибки бюджетов
+	ErrBudgetNotFound      = errors.New("budget not found")
+	ErrBudgetAlreadyExists = errors.New("budget for this user, service and category already exists")
+	ErrBudgetExceeded      = errors.New("subscription would exceed a hard budget")
+
+	// Ошибки каталога сервисов
+	ErrServiceNotFound      = errors.New("service not found")
+	ErrServiceAlreadyExists = errors.New("service name or alias is already used by another service")
+
+	// Ошибки доступа к календарной ленте
+	ErrInvalidCalendarToken = errors.New("invalid calendar token")
 
 	// Ошибки валидации
-	ErrInvalidDateFormat = errors.New("invalid date format, expected MM-YYYY")
+	ErrInvalidDateFormat = errors.New("invalid date format, expected MM-YYYY, YYYY-MM or YYYY-MM-DD")
 	ErrInvalidUUID       = errors.New("invalid UUID format")
 	ErrInvalidDateRange  = errors.New("invalid date range: start date must be before or equal to end date")
 	ErrInvalidPrice      = errors.New("price must be a positive integer")
 	ErrInvalidPagination = errors.New("invalid pagination parameters")
+	ErrValidationFailed  = errors.New("validation failed")
 
 	// Ошибки доступа к данным
 	ErrDatabaseConnection = errors.New("database connection error")
@@ -23,3 +50,79 @@ var (
 	ErrInvalidJSON          = errors.New("invalid JSON format")
 	ErrMissingRequiredField = errors.New("missing required field")
 )
+
+// TransitionError действие недопустимо в текущем статусе подписки
+type TransitionError struct {
+	Action string // pause, resume, cancel
+	Status string // текущий статус подписки
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s a subscription in status %q", e.Action, e.Status)
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrInvalidStatusTransition)
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
+
+// DetailParams параметры для перевода описания ошибки
+func (e *TransitionError) DetailParams() map[string]string {
+	return map[string]string{"action": e.Action, "status": e.Status}
+}
+
+// OverlapError период подписки пересекается с другими подписками того же
+// пользователя на тот же сервис
+type OverlapError struct {
+	SubscriptionIDs []int
+}
+
+func (e *OverlapError) Error() string {
+	return "subscription overlaps existing subscriptions " + e.ids()
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrAlreadyExists)
+func (e *OverlapError) Unwrap() error {
+	return ErrAlreadyExists
+}
+
+// DetailParams параметры для перевода описания ошибки
+func (e *OverlapError) DetailParams() map[string]string {
+	return map[string]string{"subscription_ids": e.ids()}
+}
+
+func (e *OverlapError) ids() string {
+	ids := make([]string, len(e.SubscriptionIDs))
+	for i, id := range e.SubscriptionIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// BudgetExceededError новая подписка превысила бы жесткий бюджет
+type BudgetExceededError struct {
+	BudgetID int
+	Limit    int // месячный лимит бюджета
+	Spent    int // расходы месяца без новой подписки
+	Price    int // стоимость новой подписки
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("subscription costing %d would exceed budget %d: %d of %d already spent",
+		e.Price, e.BudgetID, e.Spent, e.Limit)
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrBudgetExceeded)
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// DetailParams параметры для перевода описания ошибки
+func (e *BudgetExceededError) DetailParams() map[string]string {
+	return map[string]string{
+		"budget_id": strconv.Itoa(e.BudgetID),
+		"limit":     strconv.Itoa(e.Limit),
+		"spent":     strconv.Itoa(e.Spent),
+		"price":     strconv.Itoa(e.Price),
+	}
+}
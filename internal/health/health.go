@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Статусы проверок
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// ErrShuttingDown возвращается проверкой готовности во время остановки сервиса
+var ErrShuttingDown = errors.New("service is shutting down")
+
+// Check проверка одной зависимости сервиса
+type Check func(ctx context.Context) error
+
+// CheckResult результат отдельной проверки
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report сводный отчет о готовности сервиса
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// OK возвращает true, если все проверки прошли успешно
+func (r *Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker выполняет проверки готовности сервиса
+type Checker struct {
+	timeout      time.Duration
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+// NewChecker создает Checker с таймаутом на каждую проверку
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+// AddCheck регистрирует проверку готовности
+func (c *Checker) AddCheck(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown переводит сервис в состояние остановки:
+// проверка готовности начинает возвращать ошибку, чтобы балансировщик
+// перестал направлять трафик до закрытия соединений
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready выполняет все проверки параллельно и возвращает сводный отчет
+func (c *Checker) Ready(ctx context.Context) *Report {
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)+1),
+	}
+
+	if c.shuttingDown.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail, Error: ErrShuttingDown.Error()}
+		return report
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			started := time.Now()
+			err := nc.check(checkCtx)
+			result := CheckResult{
+				Status:     StatusOK,
+				DurationMs: time.Since(started).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}(nc)
+	}
+
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,169 @@
+// Package i18n выбирает язык ответа по заголовку Accept-Language и
+// переводит сообщения по каталогам вида {"ключ": "текст с {параметрами}"}
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bundle каталоги сообщений всех поддерживаемых языков
+type Bundle struct {
+	catalogs    map[string]map[string]string
+	defaultLang string
+}
+
+// NewBundle загружает каталоги *.json из корня fsys. Имя файла без
+// расширения — код языка. Каталог для defaultLang обязателен.
+func NewBundle(fsys fs.FS, defaultLang string) (*Bundle, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		catalogs:    make(map[string]map[string]string, len(names)),
+		defaultLang: strings.ToLower(defaultLang),
+	}
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog %s: %w", name, err)
+		}
+
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", name, err)
+		}
+
+		lang := strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))
+		b.catalogs[lang] = catalog
+	}
+
+	if _, ok := b.catalogs[b.defaultLang]; !ok {
+		return nil, fmt.Errorf("no message catalog for default language %q", defaultLang)
+	}
+
+	return b, nil
+}
+
+// Languages возвращает коды загруженных языков
+func (b *Bundle) Languages() []string {
+	langs := make([]string, 0, len(b.catalogs))
+	for lang := range b.catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Localizer подбирает язык по значению заголовка Accept-Language.
+// Если ни один из запрошенных языков не поддерживается, используется язык по умолчанию.
+func (b *Bundle) Localizer(acceptLanguage string) *Localizer {
+	return &Localizer{bundle: b, lang: b.match(acceptLanguage)}
+}
+
+// match выбирает язык с наибольшим весом q; для ru-RU подходит каталог ru
+func (b *Bundle) match(acceptLanguage string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+
+	// Стабильная сортировка сохраняет порядок клиента при равных весах
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return b.defaultLang
+		}
+		if _, ok := b.catalogs[c.tag]; ok {
+			return c.tag
+		}
+		base, _, _ := strings.Cut(c.tag, "-")
+		if _, ok := b.catalogs[base]; ok {
+			return base
+		}
+	}
+
+	return b.defaultLang
+}
+
+// Localizer переводит сообщения на выбранный для запроса язык.
+// Методы безопасны для nil: без локализатора перевод не выполняется.
+type Localizer struct {
+	bundle *Bundle
+	lang   string
+}
+
+// Language возвращает выбранный язык
+func (l *Localizer) Language() string {
+	if l == nil {
+		return ""
+	}
+	return l.lang
+}
+
+// Message возвращает перевод по ключу с подставленными параметрами {name}.
+// Если ключа нет ни в выбранном языке, ни в языке по умолчанию, ok = false.
+func (l *Localizer) Message(key string, params map[string]string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+
+	text, ok := l.bundle.catalogs[l.lang][key]
+	if !ok {
+		text, ok = l.bundle.catalogs[l.bundle.defaultLang][key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	for name, value := range params {
+		text = strings.ReplaceAll(text, "{"+name+"}", value)
+	}
+	return text, true
+}
+
+type localizerKey struct{}
+
+// WithLocalizer сохраняет локализатор запроса в контексте
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext возвращает локализатор запроса или nil
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return l
+}
@@ -0,0 +1,127 @@
+// Package ical формирует календари в формате iCalendar (RFC 5545)
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType тип содержимого календаря в HTTP ответе
+const ContentType = "text/calendar; charset=utf-8"
+
+// Максимальная длина строки содержимого в октетах без CRLF (RFC 5545, 3.1)
+const maxLineOctets = 75
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Calendar календарь с событиями
+type Calendar struct {
+	ProdID string // идентификатор продукта, сформировавшего календарь
+	Name   string // отображаемое имя календаря (X-WR-CALNAME)
+	Events []Event
+}
+
+// Event событие на весь день, возможно повторяющееся
+type Event struct {
+	UID         string
+	Stamp       time.Time // время создания или последнего изменения
+	Date        time.Time // день (первого) события
+	RRule       string    // правило повторения без префикса RRULE:, например FREQ=MONTHLY
+	Summary     string
+	Description string
+}
+
+// WriteTo записывает календарь с окончаниями строк CRLF и переносом длинных строк
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", e.Stamp.UTC().Format(dateTimeLayout))
+		line("DTSTART;VALUE=DATE", e.Date.Format(dateLayout))
+		line("DTEND;VALUE=DATE", e.Date.AddDate(0, 0, 1).Format(dateLayout))
+		if e.RRule != "" {
+			line("RRULE", e.RRule)
+		}
+		line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// FormatDate форматирует дату для значений типа DATE, например UNTIL в RRULE
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// escapeText экранирует значение типа TEXT (RFC 5545, 3.3.11)
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeFolded пишет строку содержимого, перенося ее каждые 75 октетов
+// без разрыва многобайтовых символов UTF-8
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Пробел в начале строки продолжения тоже занимает октет
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+// countingWriter считает записанные байты для результата WriteTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/lib/pq"
+)
+
+// Thresholds пороги оповещений бюджета в процентах от лимита
+type Thresholds []int
+
+// Scan читает массив INTEGER[] из базы
+func (t *Thresholds) Scan(value any) error {
+	var values pq.Int64Array
+	if err := values.Scan(value); err != nil {
+		return err
+	}
+
+	*t = make(Thresholds, len(values))
+	for i, v := range values {
+		(*t)[i] = int(v)
+	}
+	return nil
+}
+
+// Value записывает пороги как массив INTEGER[]
+func (t Thresholds) Value() (driver.Value, error) {
+	values := make(pq.Int64Array, len(t))
+	for i, v := range t {
+		values[i] = int64(v)
+	}
+	return values.Value()
+}
+
+// Budget месячный бюджет пользователя на все подписки, на один сервис, на одну
+// категорию или на сервис внутри категории
+type Budget struct {
+	ID           int        `json:"id" db:"id"`
+	UserID       string     `json:"user_id" db:"user_id"`
+	ServiceName  *string    `json:"service_name,omitempty" db:"service_name"` // nil — подписки любого сервиса
+	Category     *string    `json:"category,omitempty" db:"category"`         // nil — подписки любой категории
+	MonthlyLimit int        `json:"monthly_limit" db:"monthly_limit"`
+	Thresholds   Thresholds `json:"thresholds" db:"thresholds"`
+	Hard         bool       `json:"hard" db:"hard"` // отклонять подписки, превышающие лимит
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBudgetRequest для создания бюджета. Без thresholds используются
+// пороги по умолчанию из конфигурации.
+type CreateBudgetRequest struct {
+	UserID       string  `json:"user_id" validate:"required,uuid"`
+	ServiceName  *string `json:"service_name,omitempty" validate:"omitempty,min=1,max=255"`
+	Category     *string `json:"category,omitempty" validate:"omitempty,min=1,max=64"`
+	MonthlyLimit int     `json:"monthly_limit" validate:"required,min=1"`
+	Thresholds   []int   `json:"thresholds,omitempty" validate:"omitempty,max=10,dive,min=1,max=1000"`
+	Hard         bool    `json:"hard,omitempty"`
+}
+
+// UpdateBudgetRequest для обновления бюджета; область (пользователь, сервис, категория) не меняется
+type UpdateBudgetRequest struct {
+	MonthlyLimit *int  `json:"monthly_limit,omitempty" validate:"omitempty,min=1"`
+	Thresholds   []int `json:"thresholds,omitempty" validate:"omitempty,min=1,max=10,dive,min=1,max=1000"`
+	Hard         *bool `json:"hard,omitempty"`
+}
+
+// BudgetFilters для фильтрации при получении списка бюджетов
+type BudgetFilters struct {
+	UserID *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Hard   *bool   `json:"hard,omitempty"`
+	Limit  int     `json:"limit" validate:"min=1,max=100"`
+	Offset int     `json:"offset" validate:"min=0"`
+}
+
+// BudgetListResponse для ответа со списком бюджетов
+type BudgetListResponse struct {
+	Budgets []Budget `json:"budgets"`
+	Limit   int      `json:"limit"`
+	Offset  int      `json:"offset"`
+}
+
+// BudgetAlert оповещение о достижении порога бюджета
+type BudgetAlert struct {
+	BudgetID     int        `json:"budget_id" db:"budget_id"`
+	UserID       string     `json:"user_id" db:"-"`
+	ServiceName  *string    `json:"service_name,omitempty" db:"-"`
+	Category     *string    `json:"category,omitempty" db:"-"`
+	Month        dates.Date `json:"month" db:"month"` // первый день месяца
+	Threshold    int        `json:"threshold" db:"threshold"`
+	MonthlyLimit int        `json:"monthly_limit" db:"-"`
+	Spent        int        `json:"spent" db:"spent"`
+	FiredAt      time.Time  `json:"fired_at" db:"fired_at"`
+}
+
+// BudgetEvaluation сравнение расходов текущего месяца с лимитом бюджета
+type BudgetEvaluation struct {
+	BudgetID          int           `json:"budget_id"`
+	StartDate         dates.Date    `json:"start_date"`
+	EndDate           dates.Date    `json:"end_date"`
+	MonthlyLimit      int           `json:"monthly_limit"`
+	Spent             int           `json:"spent"`
+	Remaining         int           `json:"remaining"` // отрицательный при превышении
+	PercentUsed       float64       `json:"percent_used"`
+	Exceeded          bool          `json:"exceeded"`
+	ReachedThresholds []int         `json:"reached_thresholds"`
+	Alerts            []BudgetAlert `json:"alerts"` // оповещения, отправленные этой проверкой
+}
+
+// SetDateLayout задает формат дат в JSON ответе
+func (e *BudgetEvaluation) SetDateLayout(layout dates.Layout) {
+	e.StartDate = e.StartDate.In(layout)
+	e.EndDate = e.EndDate.In(layout)
+	for i := range e.Alerts {
+		e.Alerts[i].Month = e.Alerts[i].Month.In(layout)
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+// CalendarToken секретный токен календарной ленты пользователя. Возвращается
+// только при выпуске: в базе хранится его хеш.
+type CalendarToken struct {
+	UserID  string `json:"user_id"`
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"` // путь ленты с токеном
+}
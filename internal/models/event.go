@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+)
+
+// EventType тип автоматического изменения подписки
+type EventType string
+
+const (
+	EventExpired   EventType = "expired"   // срок истек
+	EventCancelled EventType = "cancelled" // срок истек после отмены в конце периода
+	EventRenewed   EventType = "renewed"   // подписка продлена
+)
+
+// SubscriptionEvent запись журнала изменений, выполненных фоновыми задачами
+type SubscriptionEvent struct {
+	ID             int64       `json:"id" db:"id"`
+	SubscriptionID int         `json:"subscription_id" db:"subscription_id"`
+	Event          EventType   `json:"event" db:"event"`
+	OldStatus      Status      `json:"old_status" db:"old_status"`
+	NewStatus      Status      `json:"new_status" db:"new_status"`
+	OldEndDate     *dates.Date `json:"old_end_date,omitempty" db:"old_end_date"`
+	NewEndDate     *dates.Date `json:"new_end_date,omitempty" db:"new_end_date"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+}
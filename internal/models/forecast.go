@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+)
+
+// Горизонт прогноза в месяцах
+const (
+	DefaultForecastMonths = 12
+	MaxForecastMonths     = 36
+)
+
+// PriceChange запланированное изменение цены подписки
+type PriceChange struct {
+	ID             int        `json:"id" db:"id"`
+	SubscriptionID int        `json:"subscription_id" db:"subscription_id"`
+	EffectiveDate  dates.Date `json:"effective_date" db:"effective_date"` // первое списание по новой цене — не раньше этой даты
+	Price          int        `json:"price" db:"price"`
+	AppliedAt      *time.Time `json:"applied_at,omitempty" db:"applied_at"` // nil — еще не применено
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SetDateLayout задает формат дат в JSON ответе
+func (c *PriceChange) SetDateLayout(layout dates.Layout) {
+	c.EffectiveDate = c.EffectiveDate.In(layout)
+}
+
+// SchedulePriceChangeRequest для планирования изменения цены
+type SchedulePriceChangeRequest struct {
+	EffectiveDate string `json:"effective_date" validate:"required,date"` // позже сегодняшнего дня
+	Price         int    `json:"price" validate:"required,min=1"`
+}
+
+// PriceChangeListResponse изменения цены подписки по дате вступления в силу
+type PriceChangeListResponse struct {
+	PriceChanges []PriceChange `json:"price_changes"`
+}
+
+// ForecastRequest для прогноза расходов по действующим подпискам
+type ForecastRequest struct {
+	UserID      *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	ServiceName *string `json:"service_name,omitempty"`
+	Months      int     `json:"months,omitempty" validate:"omitempty,min=1,max=36"` // по умолчанию DefaultForecastMonths
+}
+
+// ServiceAmount расходы на один сервис
+type ServiceAmount struct {
+	ServiceName string `json:"service_name"`
+	Amount      int    `json:"amount"`
+	Charges     int    `json:"charges"` // число списаний
+}
+
+// ForecastMonth прогноз расходов за календарный месяц
+type ForecastMonth struct {
+	Month    dates.Date      `json:"month"` // первый день месяца
+	Amount   int             `json:"amount"`
+	Services []ServiceAmount `json:"services"`
+}
+
+// ForecastResponse помесячный прогноз расходов и итоги по сервисам за весь горизонт
+type ForecastResponse struct {
+	Months      []ForecastMonth `json:"months"`
+	Services    []ServiceAmount `json:"services"`
+	TotalAmount int             `json:"total_amount"`
+	StartDate   dates.Date      `json:"start_date"`
+	EndDate     dates.Date      `json:"end_date"`
+	UserID      *string         `json:"user_id,omitempty"`
+	ServiceName *string         `json:"service_name,omitempty"`
+}
+
+// SetDateLayout задает формат дат в JSON ответе
+func (r *ForecastResponse) SetDateLayout(layout dates.Layout) {
+	r.StartDate = r.StartDate.In(layout)
+	r.EndDate = r.EndDate.In(layout)
+	for i := range r.Months {
+		r.Months[i].Month = r.Months[i].Month.In(layout)
+	}
+}
@@ -2,59 +2,203 @@ package models
 
 import (
 	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/lib/pq"
 )
 
 // Subscription представляет основную модель подписки
 type Subscription struct {
-	ID          int       `json:"id" db:"id"`
-	ServiceName string    `json:"service_name" db:"service_name"`
-	Price       int       `json:"price" db:"price"`
-	UserID      string    `json:"user_id" db:"user_id"`
-	StartDate   string    `json:"start_date" db:"start_date"`
-	EndDate     *string   `json:"end_date,omitempty" db:"end_date"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int         `json:"id" db:"id"`
+	ServiceName string      `json:"service_name" db:"service_name"`
+	ServiceID   *int        `json:"service_id,omitempty" db:"service_id"` // сервис каталога, если название найдено
+	Price       int         `json:"price" db:"price"`
+	UserID      string      `json:"user_id" db:"user_id"`
+	StartDate   dates.Date  `json:"start_date" db:"start_date"`
+	EndDate     *dates.Date `json:"end_date,omitempty" db:"end_date"` // включительно
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+
+	// Классификация расходов
+	Category *string        `json:"category,omitempty" db:"category"`
+	Tags     pq.StringArray `json:"tags,omitempty" db:"tags"` // в нижнем регистре, по алфавиту
+	Notes    *string        `json:"notes,omitempty" db:"notes"`
+
+	// Жизненный цикл
+	Status            Status        `json:"status" db:"status"`
+	CancelAtPeriodEnd bool          `json:"cancel_at_period_end" db:"cancel_at_period_end"`
+	CancelledAt       *time.Time    `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	AutoRenew         bool          `json:"auto_renew" db:"auto_renew"` // продлевать по достижении даты окончания
+	BillingPeriod     BillingPeriod `json:"billing_period" db:"billing_period"`
+	Pauses            []Pause       `json:"pauses,omitempty" db:"-"`
 }
 
-// CreateSubscriptionRequest для создания подписки
+// BillingPeriod периодичность списаний подписки. Цена подписки всегда
+// месячная, списание за период равно цене, умноженной на число его месяцев.
+type BillingPeriod string
+
+const (
+	BillingMonthly   BillingPeriod = "monthly"
+	BillingQuarterly BillingPeriod = "quarterly"
+	BillingYearly    BillingPeriod = "yearly"
+)
+
+// Months возвращает длину периода в месяцах; пустой период считается месячным
+func (p BillingPeriod) Months() int {
+	switch p {
+	case BillingQuarterly:
+		return 3
+	case BillingYearly:
+		return 12
+	default:
+		return 1
+	}
+}
+
+// SetDateLayout задает формат дат подписки в JSON ответе
+func (s *Subscription) SetDateLayout(layout dates.Layout) {
+	s.StartDate = s.StartDate.In(layout)
+	if s.EndDate != nil {
+		endDate := s.EndDate.In(layout)
+		s.EndDate = &endDate
+	}
+	for i := range s.Pauses {
+		s.Pauses[i].SetDateLayout(layout)
+	}
+}
+
+// CreateSubscriptionRequest для создания подписки. Даты принимаются в форматах
+// MM-YYYY, YYYY-MM и YYYY-MM-DD; месяц без дня означает его первый день
+// для даты начала и последний день для даты окончания. Без price берется
+// цена по умолчанию сервиса из каталога.
 type CreateSubscriptionRequest struct {
 	ServiceName string  `json:"service_name" validate:"required,max=255"`
-	Price       int     `json:"price" validate:"required,min=1"`
+	Price       int     `json:"price,omitempty" validate:"omitempty,min=1"`
 	UserID      string  `json:"user_id" validate:"required,uuid"`
-	StartDate   string  `json:"start_date" validate:"required"`
-	EndDate     *string `json:"end_date,omitempty"`
+	StartDate   string  `json:"start_date" validate:"required,date"`
+	EndDate     *string `json:"end_date,omitempty" validate:"omitempty,date"`
+	AutoRenew   bool    `json:"auto_renew,omitempty"` // продлевать на период по достижении end_date
+
+	// Периодичность списаний, по умолчанию monthly
+	BillingPeriod BillingPeriod `json:"billing_period,omitempty" validate:"omitempty,oneof=monthly quarterly yearly"`
+
+	// Без category берется категория сервиса из каталога
+	Category *string  `json:"category,omitempty" validate:"omitempty,min=1,max=64"`
+	Tags     []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+	Notes    *string  `json:"notes,omitempty" validate:"omitempty,max=2000"`
+
+	// Разрешить пересечение с другими подписками пользователя на тот же
+	// сервис; задается параметром запроса allow_overlap
+	AllowOverlap bool `json:"-"`
+
+	// Не проверять жесткие бюджеты; параметр запроса skip_budget_check
+	SkipBudgetCheck bool `json:"-"`
 }
 
 // UpdateSubscriptionRequest для обновления подписки
 type UpdateSubscriptionRequest struct {
 	ServiceName *string `json:"service_name,omitempty" validate:"omitempty,max=255"`
 	Price       *int    `json:"price,omitempty" validate:"omitempty,min=1"`
-	EndDate     *string `json:"end_date,omitempty"`
+	EndDate     *string `json:"end_date,omitempty" validate:"omitempty,date"`
+	AutoRenew   *bool   `json:"auto_renew,omitempty"`
+
+	BillingPeriod *BillingPeriod `json:"billing_period,omitempty" validate:"omitempty,oneof=monthly quarterly yearly"`
+
+	// Пустая category снимает категорию; tags заменяет все метки, пустой список удаляет их
+	Category *string  `json:"category,omitempty" validate:"omitempty,max=64"`
+	Tags     []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+	Notes    *string  `json:"notes,omitempty" validate:"omitempty,max=2000"` // пустая строка удаляет заметки
+
+	// Сервис каталога для нового service_name; заполняет слой сервиса
+	ServiceID *int `json:"-"`
+
+	// Разрешить пересечение с другими подписками; параметр запроса allow_overlap
+	AllowOverlap bool `json:"-"`
+
+	// Не проверять жесткие бюджеты; параметр запроса skip_budget_check
+	SkipBudgetCheck bool `json:"-"`
 }
 
-// SubscriptionFilters для фильтрации при получении списка подписок
+// SubscriptionFilters для фильтрации при получении списка подписок. Несколько
+// пользователей или названий сервисов объединяются через ИЛИ, остальные
+// фильтры — через И. Даты принимаются в тех же форматах, что и при создании.
 type SubscriptionFilters struct {
-	UserID      *string `json:"user_id,omitempty"`
-	ServiceName *string `json:"service_name,omitempty"`
-	Limit       int     `json:"limit" validate:"min=1,max=100"`
-	Offset      int     `json:"offset" validate:"min=0"`
+	UserIDs      []string `json:"user_id,omitempty" validate:"omitempty,max=50"`
+	ServiceNames []string `json:"service_name,omitempty" validate:"omitempty,max=50,dive,required,max=255"` // по вхождению без учета регистра
+	ServiceID    *int     `json:"service_id,omitempty" validate:"omitempty,min=1"`
+	Status       *Status  `json:"status,omitempty" validate:"omitempty,oneof=active paused cancelled expired"`
+	Category     *string  `json:"category,omitempty"`
+
+	// Метки: any — есть хотя бы одна из перечисленных, all — есть все
+	Tags     []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+	TagMatch string   `json:"tag_match,omitempty" validate:"omitempty,oneof=any all"`
+
+	// Цена в копейках, границы включительно
+	PriceMin *int `json:"price_min,omitempty" validate:"omitempty,min=0"`
+	PriceMax *int `json:"price_max,omitempty" validate:"omitempty,min=0"`
+
+	ActiveAt      *string `json:"active_at,omitempty" validate:"omitempty,date"`      // действует хотя бы день месяца (или в этот день)
+	StartedAfter  *string `json:"started_after,omitempty" validate:"omitempty,date"`  // начало позже указанного месяца или дня
+	StartedBefore *string `json:"started_before,omitempty" validate:"omitempty,date"` // начало раньше указанного месяца или дня
+	EndsBefore    *string `json:"ends_before,omitempty" validate:"omitempty,date"`    // окончание раньше указанного месяца или дня
+	HasEndDate    *bool   `json:"has_end_date,omitempty"`
+	CreatedSince  *string `json:"created_since,omitempty" validate:"omitempty,date"` // создана не раньше начала месяца или дня
+
+	// Границы дат, разобранные из строковых фильтров; заполняет слой сервиса
+	Dates SubscriptionDateFilters `json:"-"`
+
+	Limit  int `json:"limit" validate:"min=1,max=100"`
+	Offset int `json:"offset" validate:"min=0"`
+}
+
+// SubscriptionDateFilters границы дат для фильтрации подписок
+type SubscriptionDateFilters struct {
+	ActiveFrom    *time.Time // подписка действует хотя бы день в [ActiveFrom, ActiveTo]
+	ActiveTo      *time.Time
+	StartedAfter  *time.Time // start_date > StartedAfter
+	StartedBefore *time.Time // start_date < StartedBefore
+	EndsBefore    *time.Time // end_date < EndsBefore
+	CreatedSince  *time.Time // created_at >= CreatedSince
 }
 
 // CostCalculationRequest для расчета стоимости подписок за период
 type CostCalculationRequest struct {
 	UserID      *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
 	ServiceName *string `json:"service_name,omitempty"`
-	StartDate   string  `json:"start_date" validate:"required"`
-	EndDate     string  `json:"end_date" validate:"required"`
+	ServiceID   *int    `json:"service_id,omitempty" validate:"omitempty,min=1"` // точное совпадение с сервисом каталога
+	StartDate   string  `json:"start_date" validate:"required,date"`
+	EndDate     string  `json:"end_date" validate:"required,date"`
+	Prorate     bool    `json:"prorate,omitempty"` // учитывать неполные месяцы пропорционально дням
+	GroupBy     string  `json:"group_by,omitempty" validate:"omitempty,oneof=category tag"`
+
+	// Категория без учета регистра; задается областью бюджета, в API не выставляется
+	Category *string `json:"-"`
 }
 
 // CostCalculationResponse ответ на запрос расчета стоимости
 type CostCalculationResponse struct {
-	TotalCost   int     `json:"total_cost"`
-	StartDate   string  `json:"start_date"`
-	EndDate     string  `json:"end_date"`
-	UserID      *string `json:"user_id,omitempty"`
-	ServiceName *string `json:"service_name,omitempty"`
+	TotalCost   int         `json:"total_cost"`
+	StartDate   dates.Date  `json:"start_date"`
+	EndDate     dates.Date  `json:"end_date"`
+	Prorated    bool        `json:"prorated"`
+	UserID      *string     `json:"user_id,omitempty"`
+	ServiceName *string     `json:"service_name,omitempty"`
+	ServiceID   *int        `json:"service_id,omitempty"`
+	GroupBy     string      `json:"group_by,omitempty"`
+	Groups      []CostGroup `json:"groups,omitempty"`
+}
+
+// CostGroup стоимость подписок одной категории или метки. Подписка с
+// несколькими метками входит в группу каждой из них.
+type CostGroup struct {
+	Key       *string `json:"key"` // nil — подписки без категории или без меток
+	TotalCost int     `json:"total_cost"`
+}
+
+// SetDateLayout задает формат дат периода в JSON ответе
+func (r *CostCalculationResponse) SetDateLayout(layout dates.Layout) {
+	r.StartDate = r.StartDate.In(layout)
+	r.EndDate = r.EndDate.In(layout)
 }
 
 // SubscriptionListResponse для ответа со списком подписок
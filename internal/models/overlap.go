@@ -0,0 +1,37 @@
+package models
+
+import "github.com/IceMAN2377/market/internal/dates"
+
+// SubscriptionOverlap две подписки пользователя на один сервис
+// с пересекающимися периодами
+type SubscriptionOverlap struct {
+	UserID         string      `json:"user_id" db:"user_id"`
+	ServiceName    string      `json:"service_name" db:"service_name"`
+	SubscriptionID int         `json:"subscription_id" db:"subscription_id"`
+	OverlappingID  int         `json:"overlapping_id" db:"overlapping_id"` // больший из двух ID
+	StartDate      dates.Date  `json:"start_date" db:"start_date"`         // начало пересечения
+	EndDate        *dates.Date `json:"end_date,omitempty" db:"end_date"`   // конец пересечения; nil — без ограничения
+}
+
+// SetDateLayout задает формат дат в JSON ответе
+func (o *SubscriptionOverlap) SetDateLayout(layout dates.Layout) {
+	o.StartDate = o.StartDate.In(layout)
+	if o.EndDate != nil {
+		endDate := o.EndDate.In(layout)
+		o.EndDate = &endDate
+	}
+}
+
+// OverlapFilters для фильтрации отчета о пересечениях
+type OverlapFilters struct {
+	UserID *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Limit  int     `json:"limit" validate:"min=1,max=100"`
+	Offset int     `json:"offset" validate:"min=0"`
+}
+
+// OverlapListResponse для ответа с отчетом о пересечениях
+type OverlapListResponse struct {
+	Overlaps []SubscriptionOverlap `json:"overlaps"`
+	Limit    int                   `json:"limit"`
+	Offset   int                   `json:"offset"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "github.com/lib/pq"
+
+// Маркеры совпадений в подсвеченных фрагментах ответа
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// Маркеры совпадений, которые ставит репозиторий: управляющие символы STX и
+// ETX, удаленные из исходного текста. Слой сервиса экранирует текст как HTML
+// и только затем заменяет их на HighlightStart и HighlightStop.
+const (
+	MatchStart = "\x02"
+	MatchStop  = "\x03"
+)
+
+// SearchRequest параметры поиска подписок по названию сервиса, заметкам и меткам
+type SearchRequest struct {
+	Query  string  `json:"q" validate:"required,min=2,max=200"`
+	UserID *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Limit  int     `json:"limit" validate:"min=1,max=100"`
+	Offset int     `json:"offset" validate:"min=0"`
+}
+
+// SearchHighlights фрагменты с совпадениями, выделенными <mark>...</mark>.
+// Все значения экранированы как HTML, разметкой являются только маркеры.
+// Выделяются совпадения полнотекстового поиска; подписка, найденная только
+// по сходству написания, возвращается без выделения.
+type SearchHighlights struct {
+	ServiceName string         `json:"service_name" db:"service_name"`
+	Notes       *string        `json:"notes,omitempty" db:"notes"`
+	Tags        pq.StringArray `json:"tags,omitempty" db:"tags"` // метки, совпавшие с запросом
+}
+
+// SearchResult найденная подписка с релевантностью
+type SearchResult struct {
+	Subscription `json:"subscription"`
+	Rank         float64          `json:"rank" db:"rank"`
+	Highlights   SearchHighlights `json:"highlights" db:"highlights"`
+}
+
+// SearchResponse для ответа с результатами поиска по убыванию релевантности
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Query   string         `json:"q"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
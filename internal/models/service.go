@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Service сервис каталога. Входящий service_name подписки сопоставляется с
+// названием и синонимами без учета регистра и лишних пробелов.
+type Service struct {
+	ID           int            `json:"id" db:"id"`
+	Name         string         `json:"name" db:"name"` // каноническое название
+	Aliases      pq.StringArray `json:"aliases" db:"aliases"`
+	DefaultPrice *int           `json:"default_price,omitempty" db:"default_price"` // цена подписки, если она не указана
+	Category     *string        `json:"category,omitempty" db:"category"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateServiceRequest для добавления сервиса в каталог
+type CreateServiceRequest struct {
+	Name         string   `json:"name" validate:"required,max=255"`
+	Aliases      []string `json:"aliases,omitempty" validate:"omitempty,max=50,dive,required,max=255"`
+	DefaultPrice *int     `json:"default_price,omitempty" validate:"omitempty,min=1"`
+	Category     *string  `json:"category,omitempty" validate:"omitempty,min=1,max=64"`
+}
+
+// UpdateServiceRequest для обновления сервиса. Aliases заменяет все синонимы;
+// пустой список удаляет их.
+type UpdateServiceRequest struct {
+	Name         *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Aliases      []string `json:"aliases,omitempty" validate:"omitempty,max=50,dive,required,max=255"`
+	DefaultPrice *int     `json:"default_price,omitempty" validate:"omitempty,min=1"`
+	Category     *string  `json:"category,omitempty" validate:"omitempty,min=1,max=64"`
+}
+
+// ServiceFilters для фильтрации при получении списка сервисов
+type ServiceFilters struct {
+	Category *string `json:"category,omitempty"`
+	Limit    int     `json:"limit" validate:"min=1,max=100"`
+	Offset   int     `json:"offset" validate:"min=0"`
+}
+
+// ServiceListResponse для ответа со списком сервисов
+type ServiceListResponse struct {
+	Services []Service `json:"services"`
+	Limit    int       `json:"limit"`
+	Offset   int       `json:"offset"`
+}
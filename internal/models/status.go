@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+)
+
+// Status статус подписки
+type Status string
+
+const (
+	StatusActive    Status = "active"    // подписка действует и оплачивается
+	StatusPaused    Status = "paused"    // приостановлена, дни паузы не оплачиваются
+	StatusCancelled Status = "cancelled" // отменена пользователем
+	StatusExpired   Status = "expired"   // дата окончания прошла
+)
+
+// Pause период приостановки подписки
+type Pause struct {
+	SubscriptionID int         `json:"-" db:"subscription_id"`
+	StartDate      dates.Date  `json:"start_date" db:"start_date"`
+	EndDate        *dates.Date `json:"end_date,omitempty" db:"end_date"` // включительно; nil — пауза продолжается
+}
+
+// SetDateLayout задает формат дат паузы в JSON ответе
+func (p *Pause) SetDateLayout(layout dates.Layout) {
+	p.StartDate = p.StartDate.In(layout)
+	if p.EndDate != nil {
+		endDate := p.EndDate.In(layout)
+		p.EndDate = &endDate
+	}
+}
+
+// Contains сообщает, приходится ли день на паузу
+func (p *Pause) Contains(day time.Time) bool {
+	return !day.Before(p.StartDate.Time) && (p.EndDate == nil || !day.After(p.EndDate.Time))
+}
+
+// CancelSubscriptionRequest параметры отмены подписки
+type CancelSubscriptionRequest struct {
+	// AtPeriodEnd откладывает отмену до конца текущего оплаченного месяца
+	AtPeriodEnd bool `json:"at_period_end"`
+}
+
+// StatusChange изменение статуса подписки, применяемое репозиторием атомарно
+type StatusChange struct {
+	From Status // ожидаемый текущий статус; если он уже другой, изменение не применяется
+	To   Status
+
+	CancelAtPeriodEnd bool
+	CancelledAt       *time.Time
+	EndDate           *dates.Date // новая дата окончания, если меняется
+
+	PauseStart   *dates.Date // открыть паузу с этого дня
+	LastPauseDay *dates.Date // закрыть открытую паузу этим днем (пауза без дней удаляется)
+}
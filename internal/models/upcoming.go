@@ -0,0 +1,36 @@
+package models
+
+import "github.com/IceMAN2377/market/internal/dates"
+
+// UpcomingChargesRequest параметры запроса предстоящих списаний
+type UpcomingChargesRequest struct {
+	UserID *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Within string  `json:"within" validate:"required,window"` // окно от сегодня: 30d, 4w
+}
+
+// UpcomingCharge ближайшее списание по подписке
+type UpcomingCharge struct {
+	SubscriptionID int        `json:"subscription_id"`
+	ServiceName    string     `json:"service_name"`
+	UserID         string     `json:"user_id"`
+	ChargeDate     dates.Date `json:"charge_date"`
+	Amount         int        `json:"amount"`
+}
+
+// UpcomingChargesResponse списания в окне, отсортированные по дате
+type UpcomingChargesResponse struct {
+	Charges     []UpcomingCharge `json:"charges"`
+	TotalAmount int              `json:"total_amount"`
+	StartDate   dates.Date       `json:"start_date"`
+	EndDate     dates.Date       `json:"end_date"`
+	UserID      *string          `json:"user_id,omitempty"`
+}
+
+// SetDateLayout задает формат дат в JSON ответе
+func (r *UpcomingChargesResponse) SetDateLayout(layout dates.Layout) {
+	r.StartDate = r.StartDate.In(layout)
+	r.EndDate = r.EndDate.In(layout)
+	for i := range r.Charges {
+		r.Charges[i].ChargeDate = r.Charges[i].ChargeDate.In(layout)
+	}
+}
@@ -0,0 +1,104 @@
+// Package notify доставляет оповещения о бюджетах: в лог или на webhook
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/models"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Заголовок с подписью тела webhook: sha256=<hex HMAC-SHA256>
+const SignatureHeader = "X-Market-Signature"
+
+// Notifier отправляет оповещение о достижении порога бюджета
+type Notifier interface {
+	Notify(ctx context.Context, alert *models.BudgetAlert) error
+}
+
+// LogNotifier пишет оповещения в лог приложения
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier создает Notifier, пишущий оповещения в лог
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, alert *models.BudgetAlert) error {
+	attrs := []any{
+		"budget_id", alert.BudgetID,
+		"user_id", alert.UserID,
+		"month", alert.Month,
+		"threshold", alert.Threshold,
+		"spent", alert.Spent,
+		"monthly_limit", alert.MonthlyLimit,
+	}
+	if alert.ServiceName != nil {
+		attrs = append(attrs, "service_name", *alert.ServiceName)
+	}
+
+	n.logger.WarnContext(ctx, "Budget threshold reached", attrs...)
+	return nil
+}
+
+// WebhookNotifier отправляет оповещения POST-запросом с JSON телом
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookNotifier создает Notifier для webhook. Если secret задан, тело
+// подписывается HMAC-SHA256 в заголовке X-Market-Signature.
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert *models.BudgetAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/lib/pq"
+)
+
+// Код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolation = "23505"
+
+// Колонки бюджета во всех запросах, возвращающих models.Budget.
+// Требуют таблицу budgets без псевдонима.
+const budgetColumns = `id, user_id, service_name,
+		(SELECT c.name FROM categories c WHERE c.id = budgets.category_id) AS category,
+		monthly_limit, thresholds, hard, created_at, updated_at`
+
+func (p *postgres) CreateBudget(ctx context.Context, budget *models.Budget) (_ *models.Budget, err error) {
+	query := `
+		INSERT INTO budgets (user_id, service_name, category_id, monthly_limit, thresholds, hard)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + budgetColumns
+
+	ctx, finish := p.startQuery(ctx, "CreateBudget", query)
+	defer finish(&err)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	categoryID, err := p.categoryID(ctx, tx, budget.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.Budget
+	err = tx.GetContext(ctx, &result, query,
+		budget.UserID,
+		budget.ServiceName,
+		categoryID,
+		budget.MonthlyLimit,
+		budget.Thresholds,
+		budget.Hard,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, errs.ErrBudgetAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit budget: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (p *postgres) GetBudgetByID(ctx context.Context, id int) (_ *models.Budget, err error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = $1`
+
+	ctx, finish := p.startQuery(ctx, "GetBudgetByID", query)
+	defer finish(&err)
+
+	var budget models.Budget
+	err = p.db.GetContext(ctx, &budget, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrBudgetNotFound
+		}
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	return &budget, nil
+}
+
+func (p *postgres) GetBudgets(ctx context.Context, filters *models.BudgetFilters) (_ []models.Budget, err error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets`
+
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filters.UserID != nil {
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
+		args = append(args, *filters.UserID)
+		argIndex++
+	}
+
+	if filters.Hard != nil {
+		conditions = append(conditions, fmt.Sprintf("hard = $%d", argIndex))
+		args = append(args, *filters.Hard)
+		argIndex++
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, filters.Limit, filters.Offset)
+
+	ctx, finish := p.startQuery(ctx, "GetBudgets", query)
+	defer finish(&err)
+
+	budgets := []models.Budget{}
+	err = p.db.SelectContext(ctx, &budgets, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+
+	return budgets, nil
+}
+
+func (p *postgres) UpdateBudget(ctx context.Context, id int, updates *models.UpdateBudgetRequest) (_ *models.Budget, err error) {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	if updates.MonthlyLimit != nil {
+		setParts = append(setParts, fmt.Sprintf("monthly_limit = $%d", argIndex))
+		args = append(args, *updates.MonthlyLimit)
+		argIndex++
+	}
+
+	if updates.Thresholds != nil {
+		setParts = append(setParts, fmt.Sprintf("thresholds = $%d", argIndex))
+		args = append(args, models.Thresholds(updates.Thresholds))
+		argIndex++
+	}
+
+	if updates.Hard != nil {
+		setParts = append(setParts, fmt.Sprintf("hard = $%d", argIndex))
+		args = append(args, *updates.Hard)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return nil, errs.ErrNoFieldsToUpdate
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	query := fmt.Sprintf(`
+		UPDATE budgets
+		SET %s
+		WHERE id = $%d
+		RETURNING %s`,
+		strings.Join(setParts, ", "), argIndex, budgetColumns)
+
+	args = append(args, id)
+
+	ctx, finish := p.startQuery(ctx, "UpdateBudget", query)
+	defer finish(&err)
+
+	var budget models.Budget
+	err = p.db.GetContext(ctx, &budget, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrBudgetNotFound
+		}
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+
+	return &budget, nil
+}
+
+func (p *postgres) DeleteBudget(ctx context.Context, id int) (err error) {
+	query := `DELETE FROM budgets WHERE id = $1`
+
+	ctx, finish := p.startQuery(ctx, "DeleteBudget", query)
+	defer finish(&err)
+
+	result, err := p.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// RecordBudgetAlert отмечает порог бюджета сработавшим в месяце. Возвращает
+// false, если оповещение по этому порогу в этом месяце уже отправлялось.
+func (p *postgres) RecordBudgetAlert(ctx context.Context, alert *models.BudgetAlert) (_ bool, err error) {
+	query := `
+		INSERT INTO budget_alerts (budget_id, month, threshold, spent, fired_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (budget_id, month, threshold) DO NOTHING`
+
+	ctx, finish := p.startQuery(ctx, "RecordBudgetAlert", query)
+	defer finish(&err)
+
+	result, err := p.db.ExecContext(ctx, query, alert.BudgetID, alert.Month, alert.Threshold, alert.Spent, alert.FiredAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record budget alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteBudgetAlert снимает отметку о срабатывании порога, чтобы оповещение,
+// которое не удалось доставить, отправилось при следующей проверке
+func (p *postgres) DeleteBudgetAlert(ctx context.Context, alert *models.BudgetAlert) (err error) {
+	query := `DELETE FROM budget_alerts WHERE budget_id = $1 AND month = $2 AND threshold = $3`
+
+	ctx, finish := p.startQuery(ctx, "DeleteBudgetAlert", query)
+	defer finish(&err)
+
+	if _, err = p.db.ExecContext(ctx, query, alert.BudgetID, alert.Month, alert.Threshold); err != nil {
+		return fmt.Errorf("failed to delete budget alert: %w", err)
+	}
+
+	return nil
+}
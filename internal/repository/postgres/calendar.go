@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SaveCalendarToken сохраняет хеш токена календаря пользователя, заменяя прежний
+func (p *postgres) SaveCalendarToken(ctx context.Context, userID string, tokenHash []byte) (err error) {
+	query := `
+		INSERT INTO calendar_tokens (user_id, token_hash, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at`
+
+	ctx, finish := p.startQuery(ctx, "SaveCalendarToken", query)
+	defer finish(&err)
+
+	if _, err = p.db.ExecContext(ctx, query, userID, tokenHash); err != nil {
+		return fmt.Errorf("failed to save calendar token: %w", err)
+	}
+
+	return nil
+}
+
+// GetCalendarTokenHash возвращает хеш токена календаря пользователя
+// или nil, если токен не выпускался
+func (p *postgres) GetCalendarTokenHash(ctx context.Context, userID string) (_ []byte, err error) {
+	query := `SELECT token_hash FROM calendar_tokens WHERE user_id = $1`
+
+	ctx, finish := p.startQuery(ctx, "GetCalendarTokenHash", query)
+	defer finish(&err)
+
+	var tokenHash []byte
+	err = p.db.GetContext(ctx, &tokenHash, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get calendar token: %w", err)
+	}
+
+	return tokenHash, nil
+}
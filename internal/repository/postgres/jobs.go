@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// Колонки журнала во всех запросах, возвращающих models.SubscriptionEvent
+const eventColumns = `id, subscription_id, event, old_status, new_status, old_end_date, new_end_date, created_at`
+
+// ExpireSubscriptions переводит подписки, срок которых истек до today, в expired
+// (или cancelled при отмене в конце периода), закрывает их открытые паузы
+// и записывает изменения в журнал. Выполняется одним запросом.
+func (p *postgres) ExpireSubscriptions(ctx context.Context, today time.Time) (_ []models.SubscriptionEvent, err error) {
+	query := `
+		WITH due AS (
+			SELECT id, status
+			FROM subscriptions
+			WHERE ` + fmt.Sprintf(expiring, "$1") + `
+			FOR UPDATE
+		), expired AS (
+			UPDATE subscriptions s
+			SET status = CASE WHEN s.cancel_at_period_end THEN 'cancelled' ELSE 'expired' END,
+				updated_at = $2
+			FROM due
+			WHERE s.id = due.id
+			RETURNING s.id, due.status AS old_status, s.status AS new_status, s.end_date
+		), closed AS (
+			UPDATE subscription_pauses sp
+			SET end_date = GREATEST(sp.start_date, expired.end_date)
+			FROM expired
+			WHERE sp.subscription_id = expired.id AND sp.end_date IS NULL
+		)
+		INSERT INTO subscription_events (subscription_id, event, old_status, new_status, old_end_date, new_end_date)
+		SELECT id, new_status, old_status, new_status, end_date, end_date
+		FROM expired
+		RETURNING ` + eventColumns
+
+	ctx, finish := p.startQuery(ctx, "ExpireSubscriptions", query)
+	defer finish(&err)
+
+	var events []models.SubscriptionEvent
+	err = p.db.SelectContext(ctx, &events, query, today, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire subscriptions: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetDueRenewals возвращает до limit подписок с автопродлением, срок которых
+// истек до today
+func (p *postgres) GetDueRenewals(ctx context.Context, today time.Time, limit int) (_ []models.Subscription, err error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE status IN ('active', 'paused') AND end_date < $1
+			AND auto_renew AND NOT cancel_at_period_end
+		ORDER BY id
+		LIMIT $2`
+
+	ctx, finish := p.startQuery(ctx, "GetDueRenewals", query)
+	defer finish(&err)
+
+	var subscriptions []models.Subscription
+	err = p.db.SelectContext(ctx, &subscriptions, query, today, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due renewals: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// RenewSubscription переносит дату окончания подписки с from на to и записывает
+// продление в журнал. Если подписка тем временем изменилась (другая дата
+// окончания, отмена, выключено автопродление), возвращает nil без ошибки.
+func (p *postgres) RenewSubscription(ctx context.Context, id int, from, to dates.Date) (_ *models.SubscriptionEvent, err error) {
+	query := `
+		WITH renewed AS (
+			UPDATE subscriptions
+			SET end_date = $3, updated_at = $4
+			WHERE id = $1 AND end_date = $2
+				AND status IN ('active', 'paused') AND auto_renew AND NOT cancel_at_period_end
+			RETURNING id, status
+		)
+		INSERT INTO subscription_events (subscription_id, event, old_status, new_status, old_end_date, new_end_date)
+		SELECT id, 'renewed', status, status, $2, $3
+		FROM renewed
+		RETURNING ` + eventColumns
+
+	ctx, finish := p.startQuery(ctx, "RenewSubscription", query)
+	defer finish(&err)
+
+	var events []models.SubscriptionEvent
+	err = p.db.SelectContext(ctx, &events, query, id, from, to, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew subscription: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return &events[0], nil
+}
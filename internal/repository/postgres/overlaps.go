@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// coveredUntil последний день периода подписки: NULL, если даты окончания нет
+// или подписка продлевается автоматически. %[1]s — префикс таблицы.
+const coveredUntil = `CASE WHEN %[1]sauto_renew AND NOT %[1]scancel_at_period_end AND %[1]sstatus IN ('active', 'paused')
+	THEN NULL ELSE %[1]send_date END`
+
+// Название сервиса для сравнения подписок: без учета регистра и пробелов по краям
+const normalizedServiceName = `lower(btrim(%sservice_name))`
+
+// FindOverlaps возвращает ID других подписок того же пользователя на тот же
+// сервис, периоды которых пересекаются с периодом sub (sub.ID исключается)
+func (p *postgres) FindOverlaps(ctx context.Context, sub *models.Subscription) (_ []int, err error) {
+	query := `
+		SELECT id
+		FROM subscriptions
+		WHERE user_id = $1
+			AND ` + fmt.Sprintf(normalizedServiceName, "") + ` = lower(btrim($2))
+			AND id <> $3
+			AND start_date <= COALESCE($5::date, 'infinity')
+			AND $4 <= COALESCE(` + fmt.Sprintf(coveredUntil, "") + `, 'infinity')
+		ORDER BY id`
+
+	ctx, finish := p.startQuery(ctx, "FindOverlaps", query)
+	defer finish(&err)
+
+	// Период новой подписки тоже не ограничен при автопродлении
+	end := sub.EndDate
+	if sub.AutoRenew && !sub.CancelAtPeriodEnd {
+		end = nil
+	}
+
+	var ids []int
+	err = p.db.SelectContext(ctx, &ids, query, sub.UserID, sub.ServiceName, sub.ID, sub.StartDate, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overlapping subscriptions: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetOverlaps возвращает пары пересекающихся подписок пользователей на один сервис
+func (p *postgres) GetOverlaps(ctx context.Context, filters *models.OverlapFilters) (_ []models.SubscriptionOverlap, err error) {
+	coveredA, coveredB := fmt.Sprintf(coveredUntil, "a."), fmt.Sprintf(coveredUntil, "b.")
+
+	query := `
+		SELECT a.user_id, a.service_name, a.id AS subscription_id, b.id AS overlapping_id,
+			GREATEST(a.start_date, b.start_date) AS start_date,
+			LEAST(` + coveredA + `, ` + coveredB + `) AS end_date
+		FROM subscriptions a
+		JOIN subscriptions b ON b.user_id = a.user_id
+			AND ` + fmt.Sprintf(normalizedServiceName, "b.") + ` = ` + fmt.Sprintf(normalizedServiceName, "a.") + `
+			AND b.id > a.id
+			AND a.start_date <= COALESCE(` + coveredB + `, 'infinity')
+			AND b.start_date <= COALESCE(` + coveredA + `, 'infinity')`
+	args := []interface{}{}
+
+	if filters.UserID != nil {
+		args = append(args, *filters.UserID)
+		query += fmt.Sprintf(" WHERE a.user_id = $%d", len(args))
+	}
+
+	args = append(args, filters.Limit, filters.Offset)
+	query += fmt.Sprintf(" ORDER BY a.user_id, a.id, b.id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	ctx, finish := p.startQuery(ctx, "GetOverlaps", query)
+	defer finish(&err)
+
+	var overlaps []models.SubscriptionOverlap
+	err = p.db.SelectContext(ctx, &overlaps, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overlapping subscriptions: %w", err)
+	}
+
+	return overlaps, nil
+}
@@ -5,55 +5,150 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	errs "github.com/IceMAN2377/market/internal/errors"
 	"github.com/IceMAN2377/market/internal/models"
 	"github.com/IceMAN2377/market/internal/repository"
+	"github.com/IceMAN2377/market/internal/tracing"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type postgres struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	logger *slog.Logger
 }
 
-func NewRepository(db *sqlx.DB) repository.Repository {
+// expiring условие истечения срока на дату: подписка с автопродлением
+// не истекает, пока ее не отменили в конце периода
+const expiring = `status IN ('active', 'paused') AND end_date < %s
+		AND NOT (auto_renew AND NOT cancel_at_period_end)`
+
+// effectiveStatus вычисляет статус с учетом прошедшей даты окончания: подписка,
+// срок которой истек, считается expired (или cancelled при отмене в конце периода),
+// даже если фоновая задача еще не обновила статус
+var effectiveStatus = `
+	CASE WHEN ` + fmt.Sprintf(expiring, "CURRENT_DATE") + `
+		THEN CASE WHEN cancel_at_period_end THEN 'cancelled' ELSE 'expired' END
+		ELSE status
+	END`
+
+// Колонки подписки во всех запросах, возвращающих models.Subscription.
+// Таблица subscriptions в запросах используется без псевдонима.
+var subscriptionColumns = `id, service_name, service_id, price, user_id, start_date, end_date, created_at, updated_at,
+		` + effectiveStatus + ` AS status, cancel_at_period_end, cancelled_at, auto_renew, billing_period, notes,
+		(SELECT c.name FROM categories c WHERE c.id = subscriptions.category_id) AS category,
+		ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE st.subscription_id = subscriptions.id ORDER BY t.name) AS tags`
+
+var tracer = tracing.Tracer("github.com/IceMAN2377/market/internal/repository/postgres")
+
+func NewRepository(db *sqlx.DB, logger *slog.Logger) repository.Repository {
 	return &postgres{
-		db: db,
+		db:     db,
+		logger: logger,
 	}
 }
 
-func (p *postgres) CreateSubscription(ctx context.Context, subscription *models.Subscription) (*models.Subscription, error) {
+// startQuery открывает клиентский спан вокруг SQL-запроса. Возвращаемая функция
+// завершает спан и пишет в debug-лог длительность запроса с учетом ошибки:
+// defer finish(&err)
+func (p *postgres) startQuery(ctx context.Context, operation, query string) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, "postgres."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+	started := time.Now()
+
+	return ctx, func(err *error) {
+		attrs := []any{
+			"operation", operation,
+			"duration_ms", time.Since(started).Milliseconds(),
+		}
+		if err != nil && *err != nil {
+			attrs = append(attrs, "error", *err)
+		}
+		p.logger.DebugContext(ctx, "sql query executed", attrs...)
+
+		tracing.End(span, err)
+	}
+}
+
+func (p *postgres) CreateSubscription(ctx context.Context, subscription *models.Subscription) (_ *models.Subscription, err error) {
 	query := `
-		INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, service_name, price, user_id, start_date, end_date, created_at, updated_at`
+		INSERT INTO subscriptions (service_name, service_id, price, user_id, start_date, end_date, auto_renew, billing_period, category_id, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''))
+		RETURNING id`
 
-	var result models.Subscription
-	err := p.db.GetContext(ctx, &result,
+	ctx, finish := p.startQuery(ctx, "CreateSubscription", query)
+	defer finish(&err)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	categoryID, err := p.categoryID(ctx, tx, subscription.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = tx.GetContext(ctx, &id,
 		query,
 		subscription.ServiceName,
+		subscription.ServiceID,
 		subscription.Price,
 		subscription.UserID,
 		subscription.StartDate,
 		subscription.EndDate,
+		subscription.AutoRenew,
+		subscription.BillingPeriod,
+		categoryID,
+		subscription.Notes,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
 	}
 
+	if err = p.saveTags(ctx, tx, id, subscription.Tags); err != nil {
+		return nil, err
+	}
+
+	var result models.Subscription
+	err = tx.GetContext(ctx, &result, `SELECT `+subscriptionColumns+` FROM subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created subscription: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit subscription: %w", err)
+	}
+
 	return &result, nil
 }
 
-func (p *postgres) GetSubscriptionByID(ctx context.Context, id int) (*models.Subscription, error) {
+func (p *postgres) GetSubscriptionByID(ctx context.Context, id int) (_ *models.Subscription, err error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
 		WHERE id = $1`
 
+	ctx, finish := p.startQuery(ctx, "GetSubscriptionByID", query)
+	defer finish(&err)
+
 	var subscription models.Subscription
-	err := p.db.GetContext(ctx, &subscription, query, id)
+	err = p.db.GetContext(ctx, &subscription, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errs.ErrNotFound
@@ -61,27 +156,112 @@ func (p *postgres) GetSubscriptionByID(ctx context.Context, id int) (*models.Sub
 		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
+	if err = p.attachPauses(ctx, []*models.Subscription{&subscription}); err != nil {
+		return nil, err
+	}
+
 	return &subscription, nil
 }
 
-func (p *postgres) GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) ([]models.Subscription, error) {
+func (p *postgres) GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) (_ []models.Subscription, err error) {
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, created_at, updated_at
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions`
 
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 
-	if filters.UserID != nil {
-		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
-		args = append(args, *filters.UserID)
+	if len(filters.UserIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("user_id = ANY($%d::uuid[])", argIndex))
+		args = append(args, pq.StringArray(filters.UserIDs))
 		argIndex++
 	}
 
-	if filters.ServiceName != nil {
-		conditions = append(conditions, fmt.Sprintf("service_name ILIKE $%d", argIndex))
-		args = append(args, "%"+*filters.ServiceName+"%")
+	if len(filters.ServiceNames) > 0 {
+		patterns := make([]string, len(filters.ServiceNames))
+		for i, name := range filters.ServiceNames {
+			patterns[i] = "%" + name + "%"
+		}
+		conditions = append(conditions, fmt.Sprintf("service_name ILIKE ANY($%d)", argIndex))
+		args = append(args, pq.StringArray(patterns))
+		argIndex++
+	}
+
+	if filters.ServiceID != nil {
+		conditions = append(conditions, fmt.Sprintf("service_id = $%d", argIndex))
+		args = append(args, *filters.ServiceID)
+		argIndex++
+	}
+
+	if filters.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", effectiveStatus, argIndex))
+		args = append(args, *filters.Status)
+		argIndex++
+	}
+
+	if filters.Category != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"category_id IN (SELECT id FROM categories WHERE lower(name) = lower($%d))", argIndex))
+		args = append(args, *filters.Category)
+		argIndex++
+	}
+
+	if len(filters.Tags) > 0 {
+		conditions = append(conditions, tagCondition(filters.TagMatch, argIndex))
+		args = append(args, pq.StringArray(filters.Tags))
+		argIndex++
+	}
+
+	if filters.PriceMin != nil {
+		conditions = append(conditions, fmt.Sprintf("price >= $%d", argIndex))
+		args = append(args, *filters.PriceMin)
+		argIndex++
+	}
+
+	if filters.PriceMax != nil {
+		conditions = append(conditions, fmt.Sprintf("price <= $%d", argIndex))
+		args = append(args, *filters.PriceMax)
+		argIndex++
+	}
+
+	// Период действия пересекается с [ActiveFrom, ActiveTo]
+	if filters.Dates.ActiveFrom != nil && filters.Dates.ActiveTo != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"start_date <= $%d AND (end_date IS NULL OR end_date >= $%d)", argIndex, argIndex+1))
+		args = append(args, *filters.Dates.ActiveTo, *filters.Dates.ActiveFrom)
+		argIndex += 2
+	}
+
+	if filters.Dates.StartedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("start_date > $%d", argIndex))
+		args = append(args, *filters.Dates.StartedAfter)
+		argIndex++
+	}
+
+	if filters.Dates.StartedBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("start_date < $%d", argIndex))
+		args = append(args, *filters.Dates.StartedBefore)
+		argIndex++
+	}
+
+	if filters.Dates.EndsBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("end_date < $%d", argIndex))
+		args = append(args, *filters.Dates.EndsBefore)
+		argIndex++
+	}
+
+	if filters.HasEndDate != nil {
+		if *filters.HasEndDate {
+			conditions = append(conditions, "end_date IS NOT NULL")
+		} else {
+			conditions = append(conditions, "end_date IS NULL")
+		}
+	}
+
+	if filters.Dates.CreatedSince != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIndex))
+		args = append(args, *filters.Dates.CreatedSince)
 		argIndex++
 	}
 
@@ -92,8 +272,11 @@ func (p *postgres) GetSubscriptions(ctx context.Context, filters *models.Subscri
 	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
 	args = append(args, filters.Limit, filters.Offset)
 
+	ctx, finish := p.startQuery(ctx, "GetSubscriptions", query)
+	defer finish(&err)
+
 	var subscriptions []models.Subscription
-	err := p.db.SelectContext(ctx, &subscriptions, query, args...)
+	err = p.db.SelectContext(ctx, &subscriptions, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
 	}
@@ -102,10 +285,14 @@ func (p *postgres) GetSubscriptions(ctx context.Context, filters *models.Subscri
 		subscriptions = []models.Subscription{}
 	}
 
+	if err = p.attachPauses(ctx, pointers(subscriptions)); err != nil {
+		return nil, err
+	}
+
 	return subscriptions, nil
 }
 
-func (p *postgres) UpdateSubscription(ctx context.Context, id int, updates *models.UpdateSubscriptionRequest) (*models.Subscription, error) {
+func (p *postgres) UpdateSubscription(ctx context.Context, id int, updates *models.UpdateSubscriptionRequest) (_ *models.Subscription, err error) {
 	var setParts []string
 	var args []interface{}
 	argIndex := 1
@@ -114,6 +301,11 @@ func (p *postgres) UpdateSubscription(ctx context.Context, id int, updates *mode
 		setParts = append(setParts, fmt.Sprintf("service_name = $%d", argIndex))
 		args = append(args, *updates.ServiceName)
 		argIndex++
+
+		// Сервис каталога меняется вместе с названием; nil отвязывает подписку
+		setParts = append(setParts, fmt.Sprintf("service_id = $%d", argIndex))
+		args = append(args, updates.ServiceID)
+		argIndex++
 	}
 
 	if updates.Price != nil {
@@ -128,10 +320,44 @@ func (p *postgres) UpdateSubscription(ctx context.Context, id int, updates *mode
 		argIndex++
 	}
 
-	if len(setParts) == 0 {
+	if updates.AutoRenew != nil {
+		setParts = append(setParts, fmt.Sprintf("auto_renew = $%d", argIndex))
+		args = append(args, *updates.AutoRenew)
+		argIndex++
+	}
+
+	if updates.BillingPeriod != nil {
+		setParts = append(setParts, fmt.Sprintf("billing_period = $%d", argIndex))
+		args = append(args, *updates.BillingPeriod)
+		argIndex++
+	}
+
+	if updates.Notes != nil {
+		setParts = append(setParts, fmt.Sprintf("notes = NULLIF($%d, '')", argIndex))
+		args = append(args, *updates.Notes)
+		argIndex++
+	}
+
+	if len(setParts) == 0 && updates.Category == nil && updates.Tags == nil {
 		return nil, errs.ErrInvalidData
 	}
 
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if updates.Category != nil {
+		categoryID, err := p.categoryID(ctx, tx, updates.Category)
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("category_id = $%d", argIndex))
+		args = append(args, categoryID)
+		argIndex++
+	}
+
 	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
 	args = append(args, time.Now())
 	argIndex++
@@ -139,27 +365,57 @@ func (p *postgres) UpdateSubscription(ctx context.Context, id int, updates *mode
 	query := fmt.Sprintf(`
 		UPDATE subscriptions 
 		SET %s 
-		WHERE id = $%d
-		RETURNING id, service_name, price, user_id, start_date, end_date, created_at, updated_at`,
+		WHERE id = $%d`,
 		strings.Join(setParts, ", "), argIndex)
 
 	args = append(args, id)
 
-	var subscription models.Subscription
-	err := p.db.GetContext(ctx, &subscription, query, args...)
+	ctx, finish := p.startQuery(ctx, "UpdateSubscription", query)
+	defer finish(&err)
+
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errs.ErrNotFound
-		}
 		return nil, fmt.Errorf("failed to update subscription: %w", err)
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	if updates.Tags != nil {
+		if err = p.saveTags(ctx, tx, id, updates.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	var subscription models.Subscription
+	err = tx.GetContext(ctx, &subscription, `SELECT `+subscriptionColumns+` FROM subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated subscription: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit subscription: %w", err)
+	}
+
+	if err = p.attachPauses(ctx, []*models.Subscription{&subscription}); err != nil {
+		return nil, err
+	}
+
 	return &subscription, nil
 }
 
-func (p *postgres) DeleteSubscription(ctx context.Context, id int) error {
+func (p *postgres) DeleteSubscription(ctx context.Context, id int) (err error) {
 	query := `DELETE FROM subscriptions WHERE id = $1`
 
+	ctx, finish := p.startQuery(ctx, "DeleteSubscription", query)
+	defer finish(&err)
+
 	result, err := p.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete subscription: %w", err)
@@ -177,9 +433,11 @@ func (p *postgres) DeleteSubscription(ctx context.Context, id int) error {
 	return nil
 }
 
-func (p *postgres) CalculateCost(ctx context.Context, req *models.CostCalculationRequest) (int, error) {
+// GetSubscriptionsForPeriod возвращает подписки, пересекающиеся с периодом
+// [from, to]; стоимость по ним считает слой сервиса
+func (p *postgres) GetSubscriptionsForPeriod(ctx context.Context, req *models.CostCalculationRequest, from, to time.Time) (_ []models.Subscription, err error) {
 	query := `
-		SELECT COALESCE(SUM(price), 0) as total_cost
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
 		WHERE 1=1`
 
@@ -199,26 +457,220 @@ func (p *postgres) CalculateCost(ctx context.Context, req *models.CostCalculatio
 		argIndex++
 	}
 
+	if req.ServiceID != nil {
+		conditions = append(conditions, fmt.Sprintf("service_id = $%d", argIndex))
+		args = append(args, *req.ServiceID)
+		argIndex++
+	}
+
+	if req.Category != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"category_id IN (SELECT id FROM categories WHERE lower(name) = lower($%d))", argIndex))
+		args = append(args, *req.Category)
+		argIndex++
+	}
+
 	// Добавляем условия для периода
 	// Подписка пересекается с запрашиваемым периодом если:
 	// start_date <= end_period AND (end_date IS NULL OR end_date >= start_period)
 	conditions = append(conditions, fmt.Sprintf("start_date <= $%d", argIndex))
-	args = append(args, req.EndDate)
+	args = append(args, to)
 	argIndex++
 
 	conditions = append(conditions, fmt.Sprintf("(end_date IS NULL OR end_date >= $%d)", argIndex))
-	args = append(args, req.StartDate)
+	args = append(args, from)
 	argIndex++
 
 	if len(conditions) > 0 {
 		query += " AND " + strings.Join(conditions, " AND ")
 	}
 
-	var totalCost int
-	err := p.db.GetContext(ctx, &totalCost, query, args...)
+	ctx, finish := p.startQuery(ctx, "GetSubscriptionsForPeriod", query)
+	defer finish(&err)
+
+	var subscriptions []models.Subscription
+	err = p.db.SelectContext(ctx, &subscriptions, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions for period: %w", err)
+	}
+
+	if err = p.attachPauses(ctx, pointers(subscriptions)); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetActiveSubscriptions возвращает действующие подписки, которые могут
+// списываться в периоде [from, to]: начавшиеся не позже to и не закончившиеся
+// до from (с автопродлением дата окончания будет перенесена). serviceName
+// сопоставляется по вхождению без учета регистра.
+func (p *postgres) GetActiveSubscriptions(ctx context.Context, userID, serviceName *string, from, to time.Time) (_ []models.Subscription, err error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE ` + effectiveStatus + ` = 'active'
+			AND start_date <= $1
+			AND (end_date IS NULL OR end_date >= $2 OR (auto_renew AND NOT cancel_at_period_end))`
+	args := []interface{}{to, from}
+
+	if userID != nil {
+		args = append(args, *userID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	if serviceName != nil {
+		args = append(args, "%"+*serviceName+"%")
+		query += fmt.Sprintf(" AND service_name ILIKE $%d", len(args))
+	}
+
+	ctx, finish := p.startQuery(ctx, "GetActiveSubscriptions", query)
+	defer finish(&err)
+
+	var subscriptions []models.Subscription
+	err = p.db.SelectContext(ctx, &subscriptions, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// ChangeStatus атомарно применяет изменение статуса. Если статус подписки
+// уже не равен change.From (параллельное изменение), возвращает
+// errs.ErrInvalidStatusTransition.
+func (p *postgres) ChangeStatus(ctx context.Context, id int, change *models.StatusChange) (_ *models.Subscription, err error) {
+	query := `
+		UPDATE subscriptions
+		SET status = $1,
+			cancel_at_period_end = $2,
+			cancelled_at = COALESCE($3, cancelled_at),
+			end_date = COALESCE($4, end_date),
+			updated_at = $5
+		WHERE id = $6 AND ` + effectiveStatus + ` = $7
+		RETURNING ` + subscriptionColumns
+
+	ctx, finish := p.startQuery(ctx, "ChangeStatus", query)
+	defer finish(&err)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var subscription models.Subscription
+	err = tx.GetContext(ctx, &subscription, query,
+		change.To,
+		change.CancelAtPeriodEnd,
+		change.CancelledAt,
+		change.EndDate,
+		time.Now(),
+		id,
+		change.From,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, p.statusConflict(ctx, tx, id)
+		}
+		return nil, fmt.Errorf("failed to change subscription status: %w", err)
+	}
+
+	if change.LastPauseDay != nil {
+		// Пауза, не успевшая начаться, удаляется, остальные закрываются
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM subscription_pauses
+			WHERE subscription_id = $1 AND end_date IS NULL AND start_date > $2`,
+			id, change.LastPauseDay)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete pause: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscription_pauses SET end_date = $2
+			WHERE subscription_id = $1 AND end_date IS NULL`,
+			id, change.LastPauseDay)
+		if err != nil {
+			return nil, fmt.Errorf("failed to close pause: %w", err)
+		}
+	}
+
+	if change.PauseStart != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO subscription_pauses (subscription_id, start_date)
+			VALUES ($1, $2)`,
+			id, change.PauseStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pause: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	if err = p.attachPauses(ctx, []*models.Subscription{&subscription}); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// statusConflict различает отсутствующую подписку и параллельное изменение статуса
+func (p *postgres) statusConflict(ctx context.Context, tx *sqlx.Tx, id int) error {
+	var exists bool
+	err := tx.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE id = $1)`, id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to calculate cost: %w", err)
+		return fmt.Errorf("failed to check subscription: %w", err)
+	}
+	if !exists {
+		return errs.ErrNotFound
+	}
+	return errs.ErrInvalidStatusTransition
+}
+
+// attachPauses загружает периоды приостановки одним запросом для всех подписок
+func (p *postgres) attachPauses(ctx context.Context, subscriptions []*models.Subscription) (err error) {
+	if len(subscriptions) == 0 {
+		return nil
 	}
 
-	return totalCost, nil
+	query := `
+		SELECT subscription_id, start_date, end_date
+		FROM subscription_pauses
+		WHERE subscription_id = ANY($1)
+		ORDER BY start_date`
+
+	ctx, finish := p.startQuery(ctx, "GetPauses", query)
+	defer finish(&err)
+
+	byID := make(map[int]*models.Subscription, len(subscriptions))
+	ids := make([]int64, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		byID[s.ID] = s
+		ids = append(ids, int64(s.ID))
+	}
+
+	var pauses []models.Pause
+	err = p.db.SelectContext(ctx, &pauses, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get pauses: %w", err)
+	}
+
+	for _, pause := range pauses {
+		if s, ok := byID[pause.SubscriptionID]; ok {
+			s.Pauses = append(s.Pauses, pause)
+		}
+	}
+
+	return nil
+}
+
+// pointers возвращает указатели на элементы слайса
+func pointers(subscriptions []models.Subscription) []*models.Subscription {
+	result := make([]*models.Subscription, len(subscriptions))
+	for i := range subscriptions {
+		result[i] = &subscriptions[i]
+	}
+	return result
 }
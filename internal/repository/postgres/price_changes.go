@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/lib/pq"
+)
+
+// Колонки изменения цены во всех запросах, возвращающих models.PriceChange
+const priceChangeColumns = `id, subscription_id, effective_date, price, applied_at, created_at`
+
+func (p *postgres) CreatePriceChange(ctx context.Context, change *models.PriceChange) (_ *models.PriceChange, err error) {
+	query := `
+		INSERT INTO subscription_price_changes (subscription_id, effective_date, price)
+		VALUES ($1, $2, $3)
+		RETURNING ` + priceChangeColumns
+
+	ctx, finish := p.startQuery(ctx, "CreatePriceChange", query)
+	defer finish(&err)
+
+	var result models.PriceChange
+	err = p.db.GetContext(ctx, &result, query, change.SubscriptionID, change.EffectiveDate, change.Price)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, errs.ErrPriceChangeAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create price change: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetPriceChanges возвращает все изменения цены подписки, включая примененные,
+// по дате вступления в силу
+func (p *postgres) GetPriceChanges(ctx context.Context, subscriptionID int) (_ []models.PriceChange, err error) {
+	query := `
+		SELECT ` + priceChangeColumns + `
+		FROM subscription_price_changes
+		WHERE subscription_id = $1
+		ORDER BY effective_date`
+
+	ctx, finish := p.startQuery(ctx, "GetPriceChanges", query)
+	defer finish(&err)
+
+	var changes []models.PriceChange
+	err = p.db.SelectContext(ctx, &changes, query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetPendingPriceChanges возвращает еще не примененные изменения цены
+// подписок одним запросом, по дате вступления в силу
+func (p *postgres) GetPendingPriceChanges(ctx context.Context, subscriptionIDs []int) (_ []models.PriceChange, err error) {
+	if len(subscriptionIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + priceChangeColumns + `
+		FROM subscription_price_changes
+		WHERE subscription_id = ANY($1) AND applied_at IS NULL
+		ORDER BY subscription_id, effective_date`
+
+	ctx, finish := p.startQuery(ctx, "GetPendingPriceChanges", query)
+	defer finish(&err)
+
+	ids := make(pq.Int64Array, len(subscriptionIDs))
+	for i, id := range subscriptionIDs {
+		ids[i] = int64(id)
+	}
+
+	var changes []models.PriceChange
+	err = p.db.SelectContext(ctx, &changes, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// DeletePriceChange удаляет еще не примененное изменение цены подписки
+func (p *postgres) DeletePriceChange(ctx context.Context, subscriptionID, id int) (err error) {
+	query := `
+		DELETE FROM subscription_price_changes
+		WHERE id = $1 AND subscription_id = $2 AND applied_at IS NULL`
+
+	ctx, finish := p.startQuery(ctx, "DeletePriceChange", query)
+	defer finish(&err)
+
+	result, err := p.db.ExecContext(ctx, query, id, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete price change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrPriceChangeNotFound
+	}
+
+	return nil
+}
+
+// ApplyPriceChanges записывает в подписки цены, вступившие в силу не позже
+// today (из нескольких — последнюю), и отмечает изменения примененными.
+// Возвращает число подписок с новой ценой.
+func (p *postgres) ApplyPriceChanges(ctx context.Context, today time.Time) (_ int, err error) {
+	query := `
+		WITH due AS (
+			SELECT DISTINCT ON (subscription_id) subscription_id, price
+			FROM subscription_price_changes
+			WHERE applied_at IS NULL AND effective_date <= $1
+			ORDER BY subscription_id, effective_date DESC
+		), updated AS (
+			UPDATE subscriptions s
+			SET price = due.price, updated_at = $2
+			FROM due
+			WHERE s.id = due.subscription_id
+			RETURNING s.id
+		), applied AS (
+			UPDATE subscription_price_changes
+			SET applied_at = $2
+			WHERE applied_at IS NULL AND effective_date <= $1
+		)
+		SELECT COUNT(*) FROM updated`
+
+	ctx, finish := p.startQuery(ctx, "ApplyPriceChanges", query)
+	defer finish(&err)
+
+	var count int
+	err = p.db.GetContext(ctx, &count, query, today, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply price changes: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// tsQuery поисковый запрос $1 для полнотекстового поиска
+const tsQuery = `websearch_to_tsquery('simple', $1)`
+
+// Параметры ts_headline: выделение маркерами models.MatchStart и
+// models.MatchStop (chr(2) и chr(3)) и до двух фрагментов заметок
+const (
+	headlineMarkers = `'StartSel=' || chr(2) || ', StopSel=' || chr(3)`
+	headlineName    = headlineMarkers + ` || ', HighlightAll=true'`
+	headlineNotes   = headlineMarkers + ` || ', MaxFragments=2, MaxWords=20, MinWords=5'`
+)
+
+// headlineText исходный текст для ts_headline без символов маркеров, чтобы
+// пользовательский текст не мог их подделать
+func headlineText(column string) string {
+	return `translate(` + column + `, chr(2) || chr(3), '')`
+}
+
+// tagMatches условие совпадения метки t с запросом: целиком или по сходству написания
+const tagMatches = `(t.name % lower($1) OR to_tsvector('simple', t.name) @@ ` + tsQuery + `)`
+
+// SearchSubscriptions ищет подписки полнотекстовым поиском по названию сервиса
+// и заметкам (search_vector) и по сходству написания (pg_trgm) с названием,
+// заметками и метками. Каждый способ отбирает кандидатов по своему GIN индексу.
+// Релевантность складывается из ts_rank и наибольшего сходства запроса с
+// названием, заметками и метками.
+func (p *postgres) SearchSubscriptions(ctx context.Context, req *models.SearchRequest) (_ []models.SearchResult, err error) {
+	query := `
+		WITH matches AS (
+			SELECT id FROM subscriptions WHERE search_vector @@ ` + tsQuery + `
+			UNION
+			SELECT id FROM subscriptions WHERE $1 <% service_name
+			UNION
+			SELECT id FROM subscriptions WHERE $1 <% notes
+			UNION
+			SELECT st.subscription_id
+			FROM subscription_tags st
+			JOIN tags t ON t.id = st.tag_id
+			WHERE ` + tagMatches + `
+		)
+		SELECT ` + subscriptionColumns + `,
+			ts_rank(search_vector, ` + tsQuery + `)
+				+ GREATEST(
+					word_similarity($1, service_name),
+					COALESCE(word_similarity($1, notes), 0) * 0.5,
+					COALESCE((SELECT MAX(similarity(t.name, lower($1)))
+						FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+						WHERE st.subscription_id = subscriptions.id), 0) * 0.5
+				) AS rank,
+			ts_headline('simple', ` + headlineText("service_name") + `, ` + tsQuery + `, ` + headlineName + `) AS "highlights.service_name",
+			CASE WHEN notes IS NOT NULL
+				THEN ts_headline('simple', ` + headlineText("notes") + `, ` + tsQuery + `, ` + headlineNotes + `)
+			END AS "highlights.notes",
+			ARRAY(SELECT t.name
+				FROM subscription_tags st
+				JOIN tags t ON t.id = st.tag_id
+				WHERE st.subscription_id = subscriptions.id AND ` + tagMatches + `
+				ORDER BY t.name) AS "highlights.tags"
+		FROM subscriptions
+		WHERE id IN (SELECT id FROM matches)`
+
+	args := []interface{}{req.Query}
+
+	if req.UserID != nil {
+		args = append(args, *req.UserID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+
+	query += fmt.Sprintf(" ORDER BY rank DESC, id LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, req.Limit, req.Offset)
+
+	ctx, finish := p.startQuery(ctx, "SearchSubscriptions", query)
+	defer finish(&err)
+
+	results := []models.SearchResult{}
+	err = p.db.SelectContext(ctx, &results, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search subscriptions: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// normalizedName приводит название к виду для сопоставления с каталогом:
+// нижний регистр, без пробелов по краям, пробелы внутри схлопнуты
+const normalizedName = `lower(regexp_replace(btrim(%s), '\s+', ' ', 'g'))`
+
+// Колонки сервиса во всех запросах, возвращающих models.Service.
+// Каноническое название в aliases не входит.
+const serviceColumns = `s.id, s.name,
+		ARRAY(SELECT a.alias FROM service_aliases a
+			WHERE a.service_id = s.id AND NOT a.canonical ORDER BY a.alias) AS aliases,
+		s.default_price,
+		(SELECT c.name FROM categories c WHERE c.id = s.category_id) AS category,
+		s.created_at, s.updated_at`
+
+func (p *postgres) CreateService(ctx context.Context, service *models.Service) (_ *models.Service, err error) {
+	query := `
+		INSERT INTO services (name, default_price, category_id)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	ctx, finish := p.startQuery(ctx, "CreateService", query)
+	defer finish(&err)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	categoryID, err := p.categoryID(ctx, tx, service.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = tx.GetContext(ctx, &id, query, service.Name, service.DefaultPrice, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service: %w", err)
+	}
+
+	if err = p.saveServiceNames(ctx, tx, id, service.Name, service.Aliases); err != nil {
+		return nil, err
+	}
+
+	if err = p.linkSubscriptions(ctx, tx, id, service.Name); err != nil {
+		return nil, err
+	}
+
+	result, err := p.getService(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit service: %w", err)
+	}
+
+	return result, nil
+}
+
+func (p *postgres) GetServiceByID(ctx context.Context, id int) (_ *models.Service, err error) {
+	ctx, finish := p.startQuery(ctx, "GetServiceByID", `SELECT `+serviceColumns+` FROM services s WHERE s.id = $1`)
+	defer finish(&err)
+
+	return p.getService(ctx, p.db, id)
+}
+
+func (p *postgres) GetServices(ctx context.Context, filters *models.ServiceFilters) (_ []models.Service, err error) {
+	query := `SELECT ` + serviceColumns + ` FROM services s`
+
+	var args []interface{}
+	argIndex := 1
+
+	if filters.Category != nil {
+		query += fmt.Sprintf(" WHERE s.category_id IN (SELECT id FROM categories WHERE lower(name) = lower($%d))", argIndex)
+		args = append(args, *filters.Category)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY s.name, s.id LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, filters.Limit, filters.Offset)
+
+	ctx, finish := p.startQuery(ctx, "GetServices", query)
+	defer finish(&err)
+
+	services := []models.Service{}
+	err = p.db.SelectContext(ctx, &services, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+
+	return services, nil
+}
+
+func (p *postgres) UpdateService(ctx context.Context, id int, updates *models.UpdateServiceRequest) (_ *models.Service, err error) {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+
+	if updates.DefaultPrice != nil {
+		setParts = append(setParts, fmt.Sprintf("default_price = $%d", argIndex))
+		args = append(args, *updates.DefaultPrice)
+		argIndex++
+	}
+
+	if len(setParts) == 0 && updates.Category == nil && updates.Aliases == nil {
+		return nil, errs.ErrNoFieldsToUpdate
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if updates.Category != nil {
+		categoryID, err := p.categoryID(ctx, tx, updates.Category)
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("category_id = $%d", argIndex))
+		args = append(args, categoryID)
+		argIndex++
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	query := fmt.Sprintf(`
+		UPDATE services
+		SET %s
+		WHERE id = $%d
+		RETURNING name`,
+		strings.Join(setParts, ", "), argIndex)
+
+	args = append(args, id)
+
+	ctx, finish := p.startQuery(ctx, "UpdateService", query)
+	defer finish(&err)
+
+	var name string
+	err = tx.GetContext(ctx, &name, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("failed to update service: %w", err)
+	}
+
+	// Названия пересобираются при смене канонического названия или синонимов
+	if updates.Name != nil || updates.Aliases != nil {
+		aliases := updates.Aliases
+		if aliases == nil {
+			err = tx.SelectContext(ctx, &aliases, `
+				SELECT alias FROM service_aliases
+				WHERE service_id = $1 AND NOT canonical
+				ORDER BY alias`, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get service aliases: %w", err)
+			}
+		}
+
+		if err = p.saveServiceNames(ctx, tx, id, name, aliases); err != nil {
+			return nil, err
+		}
+
+		if err = p.linkSubscriptions(ctx, tx, id, name); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := p.getService(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit service: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteService удаляет сервис из каталога; подписки остаются со своим
+// service_name, а service_id сбрасывается внешним ключом
+func (p *postgres) DeleteService(ctx context.Context, id int) (err error) {
+	query := `DELETE FROM services WHERE id = $1`
+
+	ctx, finish := p.startQuery(ctx, "DeleteService", query)
+	defer finish(&err)
+
+	result, err := p.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrServiceNotFound
+	}
+
+	return nil
+}
+
+// MatchService ищет сервис каталога по названию или синониму. Если
+// совпадения нет, возвращает nil без ошибки.
+func (p *postgres) MatchService(ctx context.Context, name string) (_ *models.Service, err error) {
+	query := `
+		SELECT ` + serviceColumns + `
+		FROM services s
+		JOIN service_aliases m ON m.service_id = s.id
+		WHERE m.normalized = ` + fmt.Sprintf(normalizedName, "$1")
+
+	ctx, finish := p.startQuery(ctx, "MatchService", query)
+	defer finish(&err)
+
+	var service models.Service
+	err = p.db.GetContext(ctx, &service, query, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to match service: %w", err)
+	}
+
+	return &service, nil
+}
+
+// getService читает сервис вне или внутри транзакции
+func (p *postgres) getService(ctx context.Context, q sqlx.QueryerContext, id int) (*models.Service, error) {
+	query := `SELECT ` + serviceColumns + ` FROM services s WHERE s.id = $1`
+
+	var service models.Service
+	err := sqlx.GetContext(ctx, q, &service, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	return &service, nil
+}
+
+// saveServiceNames заменяет названия сервиса для сопоставления: каноническое
+// название и синонимы. Совпадающие после нормализации синонимы схлопываются;
+// занятое другим сервисом название дает errs.ErrServiceAlreadyExists.
+func (p *postgres) saveServiceNames(ctx context.Context, tx *sqlx.Tx, id int, name string, aliases []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM service_aliases WHERE service_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service aliases: %w", err)
+	}
+
+	names := append([]string{name}, aliases...)
+	normalized := fmt.Sprintf(normalizedName, "t.alias")
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO service_aliases (normalized, alias, canonical, service_id)
+		SELECT DISTINCT ON (`+normalized+`) `+normalized+`, btrim(t.alias), t.ord = 1, $1::int
+		FROM unnest($2::text[]) WITH ORDINALITY AS t(alias, ord)
+		ORDER BY `+normalized+`, t.ord`,
+		id, pq.StringArray(names))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return errs.ErrServiceAlreadyExists
+		}
+		return fmt.Errorf("failed to save service aliases: %w", err)
+	}
+
+	return nil
+}
+
+// linkSubscriptions привязывает к сервису подписки без сервиса, название которых
+// совпало с одним из названий каталога, и приводит service_name привязанных
+// подписок к каноническому
+func (p *postgres) linkSubscriptions(ctx context.Context, tx *sqlx.Tx, id int, name string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE subscriptions
+		SET service_id = $1, service_name = $2, updated_at = $3
+		WHERE (service_id = $1 AND service_name <> $2)
+			OR (service_id IS NULL AND `+fmt.Sprintf(normalizedName, "service_name")+` IN (
+				SELECT normalized FROM service_aliases WHERE service_id = $1))`,
+		id, name, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to link subscriptions to service: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// categoryID возвращает ID категории по названию без учета регистра, создавая
+// ее при необходимости. Для nil или пустого названия возвращает nil.
+func (p *postgres) categoryID(ctx context.Context, tx *sqlx.Tx, name *string) (*int, error) {
+	if name == nil || *name == "" {
+		return nil, nil
+	}
+
+	var id int
+	err := tx.GetContext(ctx, &id, `
+		WITH created AS (
+			INSERT INTO categories (name) VALUES ($1)
+			ON CONFLICT ((lower(name))) DO NOTHING
+			RETURNING id
+		)
+		SELECT id FROM created
+		UNION ALL
+		SELECT id FROM categories WHERE lower(name) = lower($1)
+		LIMIT 1`, *name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save category: %w", err)
+	}
+
+	return &id, nil
+}
+
+// saveTags заменяет метки подписки; недостающие метки создаются
+func (p *postgres) saveTags(ctx context.Context, tx *sqlx.Tx, subscriptionID int, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM subscription_tags WHERE subscription_id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription tags: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tags (name)
+		SELECT DISTINCT unnest($1::text[])
+		ON CONFLICT (name) DO NOTHING`, pq.StringArray(tags))
+	if err != nil {
+		return fmt.Errorf("failed to save tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO subscription_tags (subscription_id, tag_id)
+		SELECT $1::int, id FROM tags WHERE name = ANY($2)`,
+		subscriptionID, pq.StringArray(tags))
+	if err != nil {
+		return fmt.Errorf("failed to save subscription tags: %w", err)
+	}
+
+	return nil
+}
+
+// tagCondition условие на метки подписки: any — есть хотя бы одна из tags,
+// all — есть все. arg — номер параметра со списком меток.
+func tagCondition(match string, arg int) string {
+	tagged := fmt.Sprintf(`
+		SELECT DISTINCT t.name
+		FROM subscription_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = subscriptions.id AND t.name = ANY($%d)`, arg)
+
+	if match == "all" {
+		return fmt.Sprintf("(SELECT COUNT(*) FROM (%s) matched) = cardinality($%d::text[])", tagged, arg)
+	}
+	return "EXISTS (" + tagged + ")"
+}
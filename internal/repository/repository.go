@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"github.com/IceMAN2377/market/internal/dates"
 	"github.com/IceMAN2377/market/internal/models"
+	"time"
 )
 
 type Repository interface {
@@ -11,5 +13,45 @@ type Repository interface {
 	GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) ([]models.Subscription, error)
 	UpdateSubscription(ctx context.Context, id int, updates *models.UpdateSubscriptionRequest) (*models.Subscription, error)
 	DeleteSubscription(ctx context.Context, id int) error
-	CalculateCost(ctx context.Context, req *models.CostCalculationRequest) (int, error)
+	ChangeStatus(ctx context.Context, id int, change *models.StatusChange) (*models.Subscription, error)
+	GetSubscriptionsForPeriod(ctx context.Context, req *models.CostCalculationRequest, from, to time.Time) ([]models.Subscription, error)
+	GetActiveSubscriptions(ctx context.Context, userID, serviceName *string, from, to time.Time) ([]models.Subscription, error)
+	SearchSubscriptions(ctx context.Context, req *models.SearchRequest) ([]models.SearchResult, error)
+
+	// Пересечения подписок пользователя на один сервис
+	FindOverlaps(ctx context.Context, sub *models.Subscription) ([]int, error)
+	GetOverlaps(ctx context.Context, filters *models.OverlapFilters) ([]models.SubscriptionOverlap, error)
+
+	// Запланированные изменения цены
+	CreatePriceChange(ctx context.Context, change *models.PriceChange) (*models.PriceChange, error)
+	GetPriceChanges(ctx context.Context, subscriptionID int) ([]models.PriceChange, error)
+	GetPendingPriceChanges(ctx context.Context, subscriptionIDs []int) ([]models.PriceChange, error)
+	DeletePriceChange(ctx context.Context, subscriptionID, id int) error
+
+	// Каталог сервисов
+	CreateService(ctx context.Context, service *models.Service) (*models.Service, error)
+	GetServiceByID(ctx context.Context, id int) (*models.Service, error)
+	GetServices(ctx context.Context, filters *models.ServiceFilters) ([]models.Service, error)
+	UpdateService(ctx context.Context, id int, updates *models.UpdateServiceRequest) (*models.Service, error)
+	DeleteService(ctx context.Context, id int) error
+	MatchService(ctx context.Context, name string) (*models.Service, error)
+
+	// Бюджеты
+	CreateBudget(ctx context.Context, budget *models.Budget) (*models.Budget, error)
+	GetBudgetByID(ctx context.Context, id int) (*models.Budget, error)
+	GetBudgets(ctx context.Context, filters *models.BudgetFilters) ([]models.Budget, error)
+	UpdateBudget(ctx context.Context, id int, updates *models.UpdateBudgetRequest) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, id int) error
+	RecordBudgetAlert(ctx context.Context, alert *models.BudgetAlert) (bool, error)
+	DeleteBudgetAlert(ctx context.Context, alert *models.BudgetAlert) error
+
+	// Токены календарной ленты
+	SaveCalendarToken(ctx context.Context, userID string, tokenHash []byte) error
+	GetCalendarTokenHash(ctx context.Context, userID string) ([]byte, error)
+
+	// Фоновые задачи
+	ExpireSubscriptions(ctx context.Context, today time.Time) ([]models.SubscriptionEvent, error)
+	GetDueRenewals(ctx context.Context, today time.Time, limit int) ([]models.Subscription, error)
+	RenewSubscription(ctx context.Context, id int, from, to dates.Date) (*models.SubscriptionEvent, error)
+	ApplyPriceChanges(ctx context.Context, today time.Time) (int, error)
 }
@@ -0,0 +1,381 @@
+package budget
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/notify"
+	"github.com/IceMAN2377/market/internal/repository"
+	"github.com/IceMAN2377/market/internal/service"
+	"github.com/IceMAN2377/market/internal/service/subscription"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/IceMAN2377/market/internal/validation"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Сколько бюджетов читается за один запрос при проверке всех бюджетов
+const pageSize = 100
+
+type budget struct {
+	repo       repository.Repository
+	notifier   notify.Notifier
+	thresholds []int
+	validator  *validation.Validator
+	logger     *slog.Logger
+}
+
+var tracer = tracing.Tracer("github.com/IceMAN2377/market/internal/service/budget")
+
+// NewService создает сервис бюджетов. thresholds — пороги оповещений по
+// умолчанию для бюджетов, созданных без своих порогов.
+func NewService(repo repository.Repository, notifier notify.Notifier, thresholds []int, logger *slog.Logger) service.BudgetService {
+	return &budget{
+		repo:       repo,
+		notifier:   notifier,
+		thresholds: normalizeThresholds(thresholds),
+		// Дат в запросах бюджетов нет, диапазон лет не используется
+		validator: validation.New(dates.Bounds{}),
+		logger:    logger,
+	}
+}
+
+func (s *budget) CreateBudget(ctx context.Context, req *models.CreateBudgetRequest) (_ *models.Budget, err error) {
+	ctx, span := tracer.Start(ctx, "budget.CreateBudget")
+	defer tracing.End(span, &err)
+
+	if req.ServiceName != nil {
+		trimmed := strings.TrimSpace(*req.ServiceName)
+		req.ServiceName = &trimmed
+	}
+	if req.Category != nil {
+		trimmed := strings.TrimSpace(*req.Category)
+		req.Category = &trimmed
+	}
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+
+	thresholds := s.thresholds
+	if len(req.Thresholds) > 0 {
+		thresholds = normalizeThresholds(req.Thresholds)
+	}
+
+	return s.repo.CreateBudget(ctx, &models.Budget{
+		UserID:       req.UserID,
+		ServiceName:  req.ServiceName,
+		Category:     req.Category,
+		MonthlyLimit: req.MonthlyLimit,
+		Thresholds:   thresholds,
+		Hard:         req.Hard,
+	})
+}
+
+func (s *budget) GetBudgetByID(ctx context.Context, id int) (_ *models.Budget, err error) {
+	ctx, span := tracer.Start(ctx, "budget.GetBudgetByID")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("budget.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	return s.repo.GetBudgetByID(ctx, id)
+}
+
+func (s *budget) GetBudgets(ctx context.Context, filters *models.BudgetFilters) (_ *models.BudgetListResponse, err error) {
+	ctx, span := tracer.Start(ctx, "budget.GetBudgets")
+	defer tracing.End(span, &err)
+
+	if filters.Limit <= 0 {
+		filters.Limit = 10
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	if err := s.validator.Struct(filters).Err(); err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.repo.GetBudgets(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BudgetListResponse{
+		Budgets: budgets,
+		Limit:   filters.Limit,
+		Offset:  filters.Offset,
+	}, nil
+}
+
+func (s *budget) UpdateBudget(ctx context.Context, id int, req *models.UpdateBudgetRequest) (_ *models.Budget, err error) {
+	ctx, span := tracer.Start(ctx, "budget.UpdateBudget")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("budget.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+	if req.Thresholds != nil {
+		req.Thresholds = normalizeThresholds(req.Thresholds)
+	}
+
+	return s.repo.UpdateBudget(ctx, id, req)
+}
+
+func (s *budget) DeleteBudget(ctx context.Context, id int) (err error) {
+	ctx, span := tracer.Start(ctx, "budget.DeleteBudget")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("budget.id", id))
+
+	if id <= 0 {
+		return errs.ErrInvalidData
+	}
+
+	return s.repo.DeleteBudget(ctx, id)
+}
+
+// EvaluateBudget сравнивает расходы текущего месяца с лимитом бюджета. С notify
+// отправляет оповещения по впервые достигнутым порогам, без него ничего не меняет.
+func (s *budget) EvaluateBudget(ctx context.Context, id int, notify bool) (_ *models.BudgetEvaluation, err error) {
+	ctx, span := tracer.Start(ctx, "budget.EvaluateBudget")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("budget.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	b, err := s.repo.GetBudgetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(ctx, b, currentMonth(), notify)
+}
+
+// EvaluateBudgets проверяет все бюджеты (фоновая задача) и возвращает
+// число отправленных оповещений
+func (s *budget) EvaluateBudgets(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "budget.EvaluateBudgets")
+	defer tracing.End(span, &err)
+
+	month := currentMonth()
+	sent := 0
+	for offset := 0; ; offset += pageSize {
+		budgets, err := s.repo.GetBudgets(ctx, &models.BudgetFilters{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return sent, err
+		}
+
+		for i := range budgets {
+			evaluation, err := s.evaluate(ctx, &budgets[i], month, true)
+			if err != nil {
+				return sent, err
+			}
+			sent += len(evaluation.Alerts)
+		}
+
+		if len(budgets) < pageSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("budget.alerts", sent))
+	return sent, nil
+}
+
+// CheckSubscription отклоняет новую или измененную подписку, если с ней расходы
+// месяца превысят лимит жесткого бюджета пользователя. Проверяется текущий месяц
+// либо месяц начала подписки, если она начинается позже. Для сохраненной
+// подписки (sub.ID задан) в расходах учитывается новая версия вместо старой, и
+// изменение, которое не увеличивает расходы, не отклоняется.
+func (s *budget) CheckSubscription(ctx context.Context, sub *models.Subscription) (err error) {
+	ctx, span := tracer.Start(ctx, "budget.CheckSubscription")
+	defer tracing.End(span, &err)
+
+	month := currentMonth()
+	if sub.StartDate.After(month) {
+		month = dates.StartOfMonth(sub.StartDate.Time)
+	}
+	if sub.EndDate != nil && sub.EndDate.Before(month) {
+		return nil
+	}
+
+	hard := true
+	for offset := 0; ; offset += pageSize {
+		budgets, err := s.repo.GetBudgets(ctx, &models.BudgetFilters{UserID: &sub.UserID, Hard: &hard, Limit: pageSize, Offset: offset})
+		if err != nil {
+			return err
+		}
+
+		for i := range budgets {
+			if err := s.checkBudget(ctx, &budgets[i], sub, month); err != nil {
+				return err
+			}
+		}
+
+		if len(budgets) < pageSize {
+			return nil
+		}
+	}
+}
+
+// checkBudget проверяет подписку по одному жесткому бюджету
+func (s *budget) checkBudget(ctx context.Context, b *models.Budget, sub *models.Subscription, month time.Time) error {
+	if !covers(b, sub) {
+		return nil
+	}
+
+	from, to := month, dates.EndOfMonth(month)
+	subscriptions, err := s.scope(ctx, b, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Расходы без проверяемой подписки и с ее новой версией
+	others := slices.DeleteFunc(slices.Clone(subscriptions), func(other models.Subscription) bool {
+		return sub.ID != 0 && other.ID == sub.ID
+	})
+	spent := subscription.TotalCost(others, from, to, false)
+	price := subscription.TotalCost([]models.Subscription{*sub}, from, to, false)
+
+	if spent+price > b.MonthlyLimit && spent+price > subscription.TotalCost(subscriptions, from, to, false) {
+		return &errs.BudgetExceededError{BudgetID: b.ID, Limit: b.MonthlyLimit, Spent: spent, Price: price}
+	}
+	return nil
+}
+
+// evaluate считает расходы месяца по бюджету. С notify отправляет оповещения
+// по достигнутым порогам, которые еще не срабатывали в этом месяце.
+func (s *budget) evaluate(ctx context.Context, b *models.Budget, month time.Time, notify bool) (*models.BudgetEvaluation, error) {
+	spent, err := s.spent(ctx, b, month)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &models.BudgetEvaluation{
+		BudgetID:          b.ID,
+		StartDate:         dates.NewDate(month),
+		EndDate:           dates.NewDate(dates.EndOfMonth(month)),
+		MonthlyLimit:      b.MonthlyLimit,
+		Spent:             spent,
+		Remaining:         b.MonthlyLimit - spent,
+		PercentUsed:       math.Round(float64(spent)*10000/float64(b.MonthlyLimit)) / 100,
+		Exceeded:          spent > b.MonthlyLimit,
+		ReachedThresholds: []int{},
+		Alerts:            []models.BudgetAlert{},
+	}
+
+	for _, threshold := range b.Thresholds {
+		if spent*100 < threshold*b.MonthlyLimit {
+			continue
+		}
+		evaluation.ReachedThresholds = append(evaluation.ReachedThresholds, threshold)
+		if !notify {
+			continue
+		}
+
+		alert := models.BudgetAlert{
+			BudgetID:     b.ID,
+			UserID:       b.UserID,
+			ServiceName:  b.ServiceName,
+			Category:     b.Category,
+			Month:        dates.NewDate(month),
+			Threshold:    threshold,
+			MonthlyLimit: b.MonthlyLimit,
+			Spent:        spent,
+			FiredAt:      time.Now().UTC(),
+		}
+		sent, err := s.alert(ctx, &alert)
+		if err != nil {
+			return nil, err
+		}
+		if sent {
+			evaluation.Alerts = append(evaluation.Alerts, alert)
+		}
+	}
+
+	return evaluation, nil
+}
+
+// alert отправляет оповещение, если оно еще не отправлялось. Ошибка доставки
+// не прерывает проверку: отметка снимается, и оповещение будет отправлено
+// при следующей проверке.
+func (s *budget) alert(ctx context.Context, alert *models.BudgetAlert) (bool, error) {
+	recorded, err := s.repo.RecordBudgetAlert(ctx, alert)
+	if err != nil || !recorded {
+		return false, err
+	}
+
+	if err := s.notifier.Notify(ctx, alert); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to deliver budget alert",
+			"budget_id", alert.BudgetID, "threshold", alert.Threshold, "error", err)
+		return false, s.repo.DeleteBudgetAlert(ctx, alert)
+	}
+
+	return true, nil
+}
+
+// spent возвращает расходы месяца по бюджету: полная цена за каждую
+// подписку, действующую в месяце, как в CalculateCost без prorate
+func (s *budget) spent(ctx context.Context, b *models.Budget, month time.Time) (int, error) {
+	from, to := month, dates.EndOfMonth(month)
+
+	subscriptions, err := s.scope(ctx, b, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return subscription.TotalCost(subscriptions, from, to, false), nil
+}
+
+// scope возвращает подписки в области бюджета, пересекающиеся с периодом
+func (s *budget) scope(ctx context.Context, b *models.Budget, from, to time.Time) ([]models.Subscription, error) {
+	return s.repo.GetSubscriptionsForPeriod(ctx, &models.CostCalculationRequest{
+		UserID:      &b.UserID,
+		ServiceName: b.ServiceName,
+		Category:    b.Category,
+	}, from, to)
+}
+
+// covers сообщает, относится ли подписка к бюджету. Название сервиса
+// сопоставляется без учета регистра по вхождению, как в расчете стоимости,
+// категория — целиком без учета регистра.
+func covers(b *models.Budget, sub *models.Subscription) bool {
+	if b.ServiceName != nil && !strings.Contains(strings.ToLower(sub.ServiceName), strings.ToLower(*b.ServiceName)) {
+		return false
+	}
+	if b.Category != nil && (sub.Category == nil || !strings.EqualFold(*sub.Category, *b.Category)) {
+		return false
+	}
+	return true
+}
+
+// currentMonth возвращает первый день текущего месяца (UTC)
+func currentMonth() time.Time {
+	return dates.StartOfMonth(time.Now().UTC())
+}
+
+// normalizeThresholds сортирует пороги и убирает повторы
+func normalizeThresholds(thresholds []int) []int {
+	result := slices.Clone(thresholds)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
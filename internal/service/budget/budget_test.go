@@ -0,0 +1,461 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/repository"
+)
+
+const (
+	userID      = "123e4567-e89b-12d3-a456-426614174000"
+	otherUserID = "223e4567-e89b-12d3-a456-426614174000"
+)
+
+// fakeRepository хранит бюджеты, подписки и отметки оповещений в памяти;
+// остальные методы не используются
+type fakeRepository struct {
+	repository.Repository
+	budgets       []models.Budget
+	subscriptions []models.Subscription
+	alerts        map[alertKey]bool
+	budgetPages   int // число запросов GetBudgets
+}
+
+type alertKey struct {
+	budgetID  int
+	month     string
+	threshold int
+}
+
+func key(alert *models.BudgetAlert) alertKey {
+	return alertKey{alert.BudgetID, alert.Month.String(), alert.Threshold}
+}
+
+func (r *fakeRepository) GetBudgetByID(ctx context.Context, id int) (*models.Budget, error) {
+	for _, b := range r.budgets {
+		if b.ID == id {
+			return &b, nil
+		}
+	}
+	return nil, errs.ErrBudgetNotFound
+}
+
+func (r *fakeRepository) GetBudgets(ctx context.Context, filters *models.BudgetFilters) ([]models.Budget, error) {
+	r.budgetPages++
+
+	var matched []models.Budget
+	for _, b := range r.budgets {
+		if filters.UserID != nil && b.UserID != *filters.UserID {
+			continue
+		}
+		if filters.Hard != nil && b.Hard != *filters.Hard {
+			continue
+		}
+		matched = append(matched, b)
+	}
+
+	if filters.Offset >= len(matched) {
+		return []models.Budget{}, nil
+	}
+	matched = matched[filters.Offset:]
+	if len(matched) > filters.Limit {
+		matched = matched[:filters.Limit]
+	}
+	return matched, nil
+}
+
+func (r *fakeRepository) GetSubscriptionsForPeriod(ctx context.Context, req *models.CostCalculationRequest, from, to time.Time) ([]models.Subscription, error) {
+	var result []models.Subscription
+	for _, sub := range r.subscriptions {
+		if req.UserID != nil && sub.UserID != *req.UserID {
+			continue
+		}
+		if req.ServiceName != nil && !strings.Contains(strings.ToLower(sub.ServiceName), strings.ToLower(*req.ServiceName)) {
+			continue
+		}
+		if req.Category != nil && (sub.Category == nil || !strings.EqualFold(*sub.Category, *req.Category)) {
+			continue
+		}
+		if sub.StartDate.After(to) || (sub.EndDate != nil && sub.EndDate.Before(from)) {
+			continue
+		}
+		result = append(result, sub)
+	}
+	return result, nil
+}
+
+func (r *fakeRepository) RecordBudgetAlert(ctx context.Context, alert *models.BudgetAlert) (bool, error) {
+	if r.alerts == nil {
+		r.alerts = map[alertKey]bool{}
+	}
+	if r.alerts[key(alert)] {
+		return false, nil
+	}
+	r.alerts[key(alert)] = true
+	return true, nil
+}
+
+func (r *fakeRepository) DeleteBudgetAlert(ctx context.Context, alert *models.BudgetAlert) error {
+	delete(r.alerts, key(alert))
+	return nil
+}
+
+// fakeNotifier запоминает доставленные оповещения; с err доставка не удается
+type fakeNotifier struct {
+	sent []models.BudgetAlert
+	err  error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, alert *models.BudgetAlert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, *alert)
+	return nil
+}
+
+func newTestService(repo *fakeRepository, notifier *fakeNotifier) *budget {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(repo, notifier, []int{80, 100}, logger).(*budget)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// sub подписка пользователя, действующая с 2020 года без даты окончания
+func sub(id int, serviceName string, price int, category *string) models.Subscription {
+	return models.Subscription{
+		ID:          id,
+		ServiceName: serviceName,
+		Price:       price,
+		UserID:      userID,
+		StartDate:   dates.NewDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Category:    category,
+		Status:      models.StatusActive,
+	}
+}
+
+func TestCheckSubscription(t *testing.T) {
+	existing := []models.Subscription{
+		sub(1, "Netflix", 600, ptr("streaming")),
+		sub(2, "Spotify", 300, ptr("music")),
+		sub(3, "iCloud", 100, nil),
+	}
+	ended := sub(0, "Netflix", 10000, nil)
+	ended.EndDate = ptr(dates.NewDate(time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name    string
+		budgets []models.Budget
+		sub     models.Subscription
+		budget  int // ID превышенного бюджета, 0 — подписка проходит
+	}{
+		{
+			name:    "under limit",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 2000, Hard: true}},
+			sub:     sub(0, "YouTube", 500, nil),
+		},
+		{
+			name:    "exactly at limit",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 1500, Hard: true}},
+			sub:     sub(0, "YouTube", 500, nil),
+		},
+		{
+			name:    "over limit",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 1200, Hard: true}},
+			sub:     sub(0, "YouTube", 500, nil),
+			budget:  1,
+		},
+		{
+			name:    "soft budget is not checked",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 100}},
+			sub:     sub(0, "YouTube", 500, nil),
+		},
+		{
+			name:    "budget of another user",
+			budgets: []models.Budget{{ID: 1, UserID: otherUserID, MonthlyLimit: 100, Hard: true}},
+			sub:     sub(0, "YouTube", 500, nil),
+		},
+		{
+			name:    "service budget covers matching service",
+			budgets: []models.Budget{{ID: 1, UserID: userID, ServiceName: ptr("netflix"), MonthlyLimit: 1000, Hard: true}},
+			sub:     sub(0, "Netflix Kids", 500, nil),
+			budget:  1,
+		},
+		{
+			name:    "service budget ignores other services",
+			budgets: []models.Budget{{ID: 1, UserID: userID, ServiceName: ptr("netflix"), MonthlyLimit: 1000, Hard: true}},
+			sub:     sub(0, "YouTube", 5000, nil),
+		},
+		{
+			name:    "category budget covers the category",
+			budgets: []models.Budget{{ID: 1, UserID: userID, Category: ptr("streaming"), MonthlyLimit: 1000, Hard: true}},
+			sub:     sub(0, "YouTube", 500, ptr("Streaming")),
+			budget:  1,
+		},
+		{
+			name:    "category budget ignores other categories",
+			budgets: []models.Budget{{ID: 1, UserID: userID, Category: ptr("streaming"), MonthlyLimit: 1000, Hard: true}},
+			sub:     sub(0, "Apple Music", 5000, ptr("music")),
+		},
+		{
+			name:    "category budget ignores subscriptions without category",
+			budgets: []models.Budget{{ID: 1, UserID: userID, Category: ptr("streaming"), MonthlyLimit: 1000, Hard: true}},
+			sub:     sub(0, "YouTube", 5000, nil),
+		},
+		{
+			name: "service within category",
+			budgets: []models.Budget{{ID: 1, UserID: userID, ServiceName: ptr("netflix"), Category: ptr("streaming"),
+				MonthlyLimit: 1000, Hard: true}},
+			sub:    sub(0, "Netflix", 500, ptr("streaming")),
+			budget: 1,
+		},
+		{
+			name:    "update replaces the old version",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 1100, Hard: true}},
+			sub:     sub(1, "Netflix", 700, ptr("streaming")),
+		},
+		{
+			name:    "update raising spend over limit",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 1100, Hard: true}},
+			sub:     sub(1, "Netflix", 800, ptr("streaming")),
+			budget:  1,
+		},
+		{
+			name:    "update not raising spend passes over limit",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 500, Hard: true}},
+			sub:     sub(1, "Netflix", 550, ptr("streaming")),
+		},
+		{
+			name:    "update moving into category budget",
+			budgets: []models.Budget{{ID: 1, UserID: userID, Category: ptr("streaming"), MonthlyLimit: 700, Hard: true}},
+			sub:     sub(2, "Spotify", 300, ptr("streaming")),
+			budget:  1,
+		},
+		{
+			name:    "ended before the checked month",
+			budgets: []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 100, Hard: true}},
+			sub:     ended,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{budgets: tt.budgets, subscriptions: existing}
+			svc := newTestService(repo, &fakeNotifier{})
+
+			err := svc.CheckSubscription(context.Background(), &tt.sub)
+			if tt.budget == 0 {
+				if err != nil {
+					t.Errorf("CheckSubscription() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var exceeded *errs.BudgetExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("CheckSubscription() error = %v, want *BudgetExceededError", err)
+			}
+			if exceeded.BudgetID != tt.budget {
+				t.Errorf("BudgetID = %d, want %d", exceeded.BudgetID, tt.budget)
+			}
+		})
+	}
+}
+
+func TestCheckSubscriptionPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		budgets  int // жестких бюджетов с большим лимитом до превышенного
+		exceeded bool
+		pages    int
+	}{
+		{"single page", 10, true, 1},
+		{"exceeded on the second page", pageSize + 20, true, 2},
+		{"exceeded on the third page", 2*pageSize + 5, true, 3},
+		{"full last page", pageSize - 1, false, 2},
+		{"none exceeded", 2*pageSize + 5, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{subscriptions: []models.Subscription{sub(1, "Netflix", 600, nil)}}
+			for i := 1; i <= tt.budgets; i++ {
+				repo.budgets = append(repo.budgets, models.Budget{ID: i, UserID: userID, MonthlyLimit: 1_000_000, Hard: true})
+			}
+			limit := 1_000_000
+			if tt.exceeded {
+				limit = 1000
+			}
+			last := tt.budgets + 1
+			repo.budgets = append(repo.budgets, models.Budget{ID: last, UserID: userID, MonthlyLimit: limit, Hard: true})
+			svc := newTestService(repo, &fakeNotifier{})
+
+			newSub := sub(0, "YouTube", 500, nil)
+			err := svc.CheckSubscription(context.Background(), &newSub)
+
+			var exceeded *errs.BudgetExceededError
+			if tt.exceeded {
+				if !errors.As(err, &exceeded) || exceeded.BudgetID != last {
+					t.Errorf("CheckSubscription() error = %v, want budget %d exceeded", err, last)
+				}
+			} else if err != nil {
+				t.Errorf("CheckSubscription() error = %v, want nil", err)
+			}
+			if repo.budgetPages != tt.pages {
+				t.Errorf("GetBudgets() called %d times, want %d", repo.budgetPages, tt.pages)
+			}
+		})
+	}
+}
+
+func TestEvaluateBudgetAlerts(t *testing.T) {
+	subscriptions := []models.Subscription{
+		sub(1, "Netflix", 600, ptr("streaming")),
+		sub(2, "Spotify", 300, ptr("music")),
+	}
+
+	tests := []struct {
+		name    string
+		budget  models.Budget
+		spent   int
+		reached []int
+	}{
+		{"none reached", models.Budget{ID: 1, UserID: userID, MonthlyLimit: 2000, Thresholds: models.Thresholds{50, 80, 100}}, 900, []int{}},
+		{"some reached", models.Budget{ID: 1, UserID: userID, MonthlyLimit: 1000, Thresholds: models.Thresholds{50, 80, 100}}, 900, []int{50, 80}},
+		{"threshold exactly reached", models.Budget{ID: 1, UserID: userID, MonthlyLimit: 900, Thresholds: models.Thresholds{100}}, 900, []int{100}},
+		{"over limit", models.Budget{ID: 1, UserID: userID, MonthlyLimit: 600, Thresholds: models.Thresholds{80, 100, 150}}, 900, []int{80, 100, 150}},
+		{"category scope", models.Budget{ID: 1, UserID: userID, Category: ptr("music"), MonthlyLimit: 300, Thresholds: models.Thresholds{100}}, 300, []int{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{budgets: []models.Budget{tt.budget}, subscriptions: subscriptions}
+			notifier := &fakeNotifier{}
+			svc := newTestService(repo, notifier)
+			ctx := context.Background()
+
+			// Без notify оценка ничего не отправляет и не отмечает
+			evaluation, err := svc.EvaluateBudget(ctx, 1, false)
+			if err != nil {
+				t.Fatalf("EvaluateBudget() error = %v", err)
+			}
+			if evaluation.Spent != tt.spent || !equal(evaluation.ReachedThresholds, tt.reached) {
+				t.Errorf("spent %d, reached %v; want %d, %v", evaluation.Spent, evaluation.ReachedThresholds, tt.spent, tt.reached)
+			}
+			if len(evaluation.Alerts) != 0 || len(notifier.sent) != 0 || len(repo.alerts) != 0 {
+				t.Fatalf("read-only evaluation sent %d alerts and recorded %d", len(notifier.sent), len(repo.alerts))
+			}
+
+			// Первая оценка с notify оповещает о каждом достигнутом пороге
+			evaluation, err = svc.EvaluateBudget(ctx, 1, true)
+			if err != nil {
+				t.Fatalf("EvaluateBudget() error = %v", err)
+			}
+			if len(evaluation.Alerts) != len(tt.reached) || len(notifier.sent) != len(tt.reached) {
+				t.Fatalf("got %d alerts, %d delivered; want %d", len(evaluation.Alerts), len(notifier.sent), len(tt.reached))
+			}
+			for i, alert := range notifier.sent {
+				if alert.Threshold != tt.reached[i] || alert.Spent != tt.spent || alert.BudgetID != 1 {
+					t.Errorf("alert %d = %+v, want threshold %d", i, alert, tt.reached[i])
+				}
+			}
+
+			// Повторная оценка в том же месяце не повторяет оповещения
+			evaluation, err = svc.EvaluateBudget(ctx, 1, true)
+			if err != nil {
+				t.Fatalf("EvaluateBudget() error = %v", err)
+			}
+			if len(evaluation.Alerts) != 0 || len(notifier.sent) != len(tt.reached) {
+				t.Errorf("repeated evaluation sent %d alerts, want none", len(evaluation.Alerts))
+			}
+		})
+	}
+}
+
+func TestEvaluateBudgetNotifyFailure(t *testing.T) {
+	repo := &fakeRepository{
+		budgets:       []models.Budget{{ID: 1, UserID: userID, MonthlyLimit: 1000, Thresholds: models.Thresholds{50, 80}}},
+		subscriptions: []models.Subscription{sub(1, "Netflix", 900, nil)},
+	}
+	notifier := &fakeNotifier{err: errors.New("webhook unavailable")}
+	svc := newTestService(repo, notifier)
+	ctx := context.Background()
+
+	// Недоставленное оповещение не считается отправленным, отметка снимается
+	evaluation, err := svc.EvaluateBudget(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("EvaluateBudget() error = %v", err)
+	}
+	if len(evaluation.Alerts) != 0 {
+		t.Errorf("got %d alerts, want none after failed delivery", len(evaluation.Alerts))
+	}
+	if len(repo.alerts) != 0 {
+		t.Errorf("%d alert marks left after failed delivery, want none", len(repo.alerts))
+	}
+
+	// Следующая оценка отправляет их снова
+	notifier.err = nil
+	evaluation, err = svc.EvaluateBudget(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("EvaluateBudget() error = %v", err)
+	}
+	if len(evaluation.Alerts) != 2 || len(notifier.sent) != 2 || len(repo.alerts) != 2 {
+		t.Errorf("got %d alerts, %d delivered, %d recorded; want 2 each",
+			len(evaluation.Alerts), len(notifier.sent), len(repo.alerts))
+	}
+}
+
+func TestEvaluateBudgets(t *testing.T) {
+	repo := &fakeRepository{subscriptions: []models.Subscription{sub(1, "Netflix", 900, nil)}}
+	for i := 1; i <= pageSize+5; i++ {
+		limit := 1_000_000
+		if i%50 == 0 {
+			limit = 1000 // достигнут порог 80
+		}
+		repo.budgets = append(repo.budgets, models.Budget{ID: i, UserID: userID, MonthlyLimit: limit, Thresholds: models.Thresholds{80, 100}})
+	}
+	notifier := &fakeNotifier{}
+	svc := newTestService(repo, notifier)
+
+	sent, err := svc.EvaluateBudgets(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateBudgets() error = %v", err)
+	}
+	if sent != 2 || len(notifier.sent) != 2 {
+		t.Errorf("sent %d alerts, delivered %d; want 2", sent, len(notifier.sent))
+	}
+	if repo.budgetPages != 2 {
+		t.Errorf("GetBudgets() called %d times, want 2", repo.budgetPages)
+	}
+
+	sent, err = svc.EvaluateBudgets(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateBudgets() error = %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("repeated run sent %d alerts, want none", sent)
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
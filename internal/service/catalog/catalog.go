@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/repository"
+	"github.com/IceMAN2377/market/internal/service"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/IceMAN2377/market/internal/validation"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type catalog struct {
+	repo      repository.Repository
+	validator *validation.Validator
+}
+
+var tracer = tracing.Tracer("github.com/IceMAN2377/market/internal/service/catalog")
+
+// NewService создает сервис каталога
+func NewService(repo repository.Repository) service.CatalogService {
+	return &catalog{
+		repo: repo,
+		// Дат в запросах каталога нет, диапазон лет не используется
+		validator: validation.New(dates.Bounds{}),
+	}
+}
+
+func (s *catalog) CreateService(ctx context.Context, req *models.CreateServiceRequest) (_ *models.Service, err error) {
+	ctx, span := tracer.Start(ctx, "catalog.CreateService")
+	defer tracing.End(span, &err)
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Aliases = trimAll(req.Aliases)
+	req.Category = trimPtr(req.Category)
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateService(ctx, &models.Service{
+		Name:         req.Name,
+		Aliases:      req.Aliases,
+		DefaultPrice: req.DefaultPrice,
+		Category:     req.Category,
+	})
+}
+
+func (s *catalog) GetServiceByID(ctx context.Context, id int) (_ *models.Service, err error) {
+	ctx, span := tracer.Start(ctx, "catalog.GetServiceByID")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("service.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	return s.repo.GetServiceByID(ctx, id)
+}
+
+func (s *catalog) GetServices(ctx context.Context, filters *models.ServiceFilters) (_ *models.ServiceListResponse, err error) {
+	ctx, span := tracer.Start(ctx, "catalog.GetServices")
+	defer tracing.End(span, &err)
+
+	if filters.Limit <= 0 {
+		filters.Limit = 10
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	if err := s.validator.Struct(filters).Err(); err != nil {
+		return nil, err
+	}
+
+	services, err := s.repo.GetServices(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ServiceListResponse{
+		Services: services,
+		Limit:    filters.Limit,
+		Offset:   filters.Offset,
+	}, nil
+}
+
+func (s *catalog) UpdateService(ctx context.Context, id int, req *models.UpdateServiceRequest) (_ *models.Service, err error) {
+	ctx, span := tracer.Start(ctx, "catalog.UpdateService")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("service.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	req.Name = trimPtr(req.Name)
+	req.Aliases = trimAll(req.Aliases)
+	req.Category = trimPtr(req.Category)
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateService(ctx, id, req)
+}
+
+func (s *catalog) DeleteService(ctx context.Context, id int) (err error) {
+	ctx, span := tracer.Start(ctx, "catalog.DeleteService")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("service.id", id))
+
+	if id <= 0 {
+		return errs.ErrInvalidData
+	}
+
+	return s.repo.DeleteService(ctx, id)
+}
+
+// trimAll обрезает пробелы в каждом синониме; nil остается nil
+func trimAll(values []string) []string {
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+func trimPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"github.com/IceMAN2377/market/internal/ical"
 	"github.com/IceMAN2377/market/internal/models"
 )
 
@@ -11,5 +12,52 @@ type Service interface {
 	GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) (*models.SubscriptionListResponse, error)
 	UpdateSubscription(ctx context.Context, id int, req *models.UpdateSubscriptionRequest) (*models.Subscription, error)
 	DeleteSubscription(ctx context.Context, id int) error
+	GetOverlaps(ctx context.Context, filters *models.OverlapFilters) (*models.OverlapListResponse, error)
+	Search(ctx context.Context, req *models.SearchRequest) (*models.SearchResponse, error)
+	PauseSubscription(ctx context.Context, id int) (*models.Subscription, error)
+	ResumeSubscription(ctx context.Context, id int) (*models.Subscription, error)
+	CancelSubscription(ctx context.Context, id int, req *models.CancelSubscriptionRequest) (*models.Subscription, error)
 	CalculateCost(ctx context.Context, req *models.CostCalculationRequest) (*models.CostCalculationResponse, error)
+	UpcomingCharges(ctx context.Context, req *models.UpcomingChargesRequest) (*models.UpcomingChargesResponse, error)
+	Forecast(ctx context.Context, req *models.ForecastRequest) (*models.ForecastResponse, error)
+
+	// Запланированные изменения цены
+	SchedulePriceChange(ctx context.Context, subscriptionID int, req *models.SchedulePriceChangeRequest) (*models.PriceChange, error)
+	GetPriceChanges(ctx context.Context, subscriptionID int) (*models.PriceChangeListResponse, error)
+	DeletePriceChange(ctx context.Context, subscriptionID, id int) error
+
+	// Календарная лента продлений
+	IssueCalendarToken(ctx context.Context, userID string) (*models.CalendarToken, error)
+	RenewalCalendar(ctx context.Context, userID, token string) (*ical.Calendar, error)
+
+	// Фоновые задачи; возвращают число измененных подписок
+	ExpireSubscriptions(ctx context.Context) (int, error)
+	RenewSubscriptions(ctx context.Context) (int, error)
+	ApplyPriceChanges(ctx context.Context) (int, error)
+}
+
+// BudgetService управляет месячными бюджетами пользователей
+type BudgetService interface {
+	CreateBudget(ctx context.Context, req *models.CreateBudgetRequest) (*models.Budget, error)
+	GetBudgetByID(ctx context.Context, id int) (*models.Budget, error)
+	GetBudgets(ctx context.Context, filters *models.BudgetFilters) (*models.BudgetListResponse, error)
+	UpdateBudget(ctx context.Context, id int, req *models.UpdateBudgetRequest) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, id int) error
+	// Проверка бюджета; с notify отправляет оповещения по достигнутым порогам
+	EvaluateBudget(ctx context.Context, id int, notify bool) (*models.BudgetEvaluation, error)
+
+	// Проверка новой или измененной подписки по жестким бюджетам
+	CheckSubscription(ctx context.Context, sub *models.Subscription) error
+
+	// Фоновая задача; возвращает число отправленных оповещений
+	EvaluateBudgets(ctx context.Context) (int, error)
+}
+
+// CatalogService управляет каталогом сервисов
+type CatalogService interface {
+	CreateService(ctx context.Context, req *models.CreateServiceRequest) (*models.Service, error)
+	GetServiceByID(ctx context.Context, id int) (*models.Service, error)
+	GetServices(ctx context.Context, filters *models.ServiceFilters) (*models.ServiceListResponse, error)
+	UpdateService(ctx context.Context, id int, req *models.UpdateServiceRequest) (*models.Service, error)
+	DeleteService(ctx context.Context, id int) error
 }
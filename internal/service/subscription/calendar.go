@@ -0,0 +1,142 @@
+package subscription
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/ical"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// Длина токена календаря в байтах до кодирования
+	calendarTokenBytes = 32
+
+	// Наибольшее число событий в календарной ленте
+	maxCalendarEvents = 1000
+)
+
+// IssueCalendarToken выпускает новый токен календарной ленты пользователя;
+// прежний токен перестает действовать
+func (s *subscription) IssueCalendarToken(ctx context.Context, userID string) (_ *models.CalendarToken, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.IssueCalendarToken")
+	defer tracing.End(span, &err)
+
+	if err := s.validateUUID(userID); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.repo.SaveCalendarToken(ctx, userID, hashToken(token)); err != nil {
+		return nil, err
+	}
+
+	return &models.CalendarToken{UserID: userID, Token: token}, nil
+}
+
+// RenewalCalendar проверяет токен и возвращает календарь с повторяющимся
+// событием в день списания каждой действующей подписки пользователя
+func (s *subscription) RenewalCalendar(ctx context.Context, userID, token string) (_ *ical.Calendar, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.RenewalCalendar")
+	defer tracing.End(span, &err)
+
+	if err := s.validateUUID(userID); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.GetCalendarTokenHash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || stored == nil || subtle.ConstantTimeCompare(stored, hashToken(token)) != 1 {
+		return nil, errs.ErrInvalidCalendarToken
+	}
+
+	active := models.StatusActive
+	subscriptions, err := s.repo.GetSubscriptions(ctx, &models.SubscriptionFilters{
+		UserIDs: []string{userID},
+		Status:  &active,
+		Limit:   maxCalendarEvents,
+	})
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("calendar.events", len(subscriptions)))
+
+	calendar := &ical.Calendar{
+		ProdID: "-//market//subscription renewals//EN",
+		Name:   "Subscription renewals",
+		Events: make([]ical.Event, 0, len(subscriptions)),
+	}
+	for _, sub := range subscriptions {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("subscription-%d@market", sub.ID),
+			Stamp:       sub.UpdatedAt,
+			Date:        sub.StartDate.Time,
+			RRule:       chargeRule(sub),
+			Summary:     fmt.Sprintf("%s: %d", sub.ServiceName, chargeAmount(sub, sub.Price)),
+			Description: fmt.Sprintf("%s charge of subscription #%d", chargeDescriptions[sub.BillingPeriod.Months()], sub.ID),
+		})
+	}
+
+	return calendar, nil
+}
+
+// chargeDescriptions название списания по длине периода в месяцах
+var chargeDescriptions = map[int]string{
+	1:  "Monthly",
+	3:  "Quarterly",
+	12: "Yearly",
+}
+
+// chargeRule правило повторения списаний, совпадающее с nextChargeDate:
+// раз в billing_period в день start_date, в коротких месяцах — в последний день
+func chargeRule(sub models.Subscription) string {
+	start := sub.StartDate.Time
+	day := start.Day()
+
+	rule := "FREQ=MONTHLY;"
+	if step := sub.BillingPeriod.Months(); step > 1 {
+		rule += "INTERVAL=" + strconv.Itoa(step) + ";"
+	}
+	switch {
+	case day == dates.DaysInMonth(start):
+		rule += "BYMONTHDAY=-1"
+	case day > 28:
+		// Последний из существующих в месяце дней 28..day
+		days := make([]string, 0, day-27)
+		for d := 28; d <= day; d++ {
+			days = append(days, strconv.Itoa(d))
+		}
+		rule += "BYMONTHDAY=" + strings.Join(days, ",") + ";BYSETPOS=-1"
+	default:
+		rule += "BYMONTHDAY=" + strconv.Itoa(day)
+	}
+
+	renews := sub.AutoRenew && !sub.CancelAtPeriodEnd
+	if sub.EndDate != nil && !renews {
+		rule += ";UNTIL=" + ical.FormatDate(sub.EndDate.Time)
+	}
+	return rule
+}
+
+// hashToken возвращает SHA-256 токена для хранения и сравнения
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
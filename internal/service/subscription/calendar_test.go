@@ -0,0 +1,50 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func TestChargeRule(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  models.Subscription
+		want string
+	}{
+		{
+			name: "monthly",
+			sub:  models.Subscription{StartDate: date("2024-01-15"), BillingPeriod: models.BillingMonthly},
+			want: "FREQ=MONTHLY;BYMONTHDAY=15",
+		},
+		{
+			name: "last day",
+			sub:  models.Subscription{StartDate: date("2024-04-30"), BillingPeriod: models.BillingMonthly},
+			want: "FREQ=MONTHLY;BYMONTHDAY=-1",
+		},
+		{
+			name: "30th",
+			sub:  models.Subscription{StartDate: date("2024-01-30"), BillingPeriod: models.BillingMonthly},
+			want: "FREQ=MONTHLY;BYMONTHDAY=28,29,30;BYSETPOS=-1",
+		},
+		{
+			name: "quarterly until end date",
+			sub: models.Subscription{StartDate: date("2024-01-15"), EndDate: datePtr("2024-12-31"),
+				BillingPeriod: models.BillingQuarterly},
+			want: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15;UNTIL=20241231",
+		},
+		{
+			name: "yearly from february 29",
+			sub:  models.Subscription{StartDate: date("2024-02-29"), BillingPeriod: models.BillingYearly},
+			want: "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chargeRule(tt.sub); got != tt.want {
+				t.Errorf("chargeRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
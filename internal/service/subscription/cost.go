@@ -0,0 +1,161 @@
+package subscription
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// TotalCost рассчитывает суммарную стоимость подписок за период [from, to],
+// округленную до целого, по тем же правилам, что и CalculateCost
+func TotalCost(subscriptions []models.Subscription, from, to time.Time, prorate bool) int {
+	var total float64
+	for _, sub := range subscriptions {
+		total += periodCost(sub, from, to, prorate)
+	}
+	return int(math.Round(total))
+}
+
+// Измерения группировки стоимости
+const (
+	GroupByCategory = "category"
+	GroupByTag      = "tag"
+)
+
+// costGroups рассчитывает стоимость подписок по категориям или меткам.
+// Подписка с несколькими метками входит в группу каждой из них, поэтому
+// сумма групп по меткам может превышать общую стоимость. Группы отсортированы
+// по убыванию стоимости; группа без категории или меток — последняя среди равных.
+func costGroups(subscriptions []models.Subscription, from, to time.Time, prorate bool, groupBy string) []models.CostGroup {
+	costs := make(map[string]float64)
+	var ungrouped float64
+	hasUngrouped := false
+
+	for _, sub := range subscriptions {
+		var keys []string
+		switch groupBy {
+		case GroupByCategory:
+			if sub.Category != nil {
+				keys = []string{*sub.Category}
+			}
+		case GroupByTag:
+			keys = sub.Tags
+		}
+
+		cost := periodCost(sub, from, to, prorate)
+		if len(keys) == 0 {
+			ungrouped += cost
+			hasUngrouped = true
+			continue
+		}
+		for _, key := range keys {
+			costs[key] += cost
+		}
+	}
+
+	groups := make([]models.CostGroup, 0, len(costs)+1)
+	for key, cost := range costs {
+		groups = append(groups, models.CostGroup{Key: &key, TotalCost: int(math.Round(cost))})
+	}
+	if hasUngrouped {
+		groups = append(groups, models.CostGroup{TotalCost: int(math.Round(ungrouped))})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost > b.TotalCost
+		}
+		if a.Key == nil || b.Key == nil {
+			return b.Key == nil && a.Key != nil
+		}
+		return *a.Key < *b.Key
+	})
+
+	return groups
+}
+
+// periodCost рассчитывает стоимость подписки за период [from, to] (обе даты
+// включительно). Дни паузы не оплачиваются. Без prorate за каждый месяц, в
+// котором есть хотя бы один оплачиваемый день, берется полная цена; с prorate —
+// доля цены, пропорциональная оплачиваемым дням месяца.
+func periodCost(sub models.Subscription, from, to time.Time, prorate bool) float64 {
+	start := later(sub.StartDate.Time, from)
+	end := to
+	if sub.EndDate != nil {
+		end = earlier(sub.EndDate.Time, to)
+	}
+
+	pauses := slices.SortedFunc(slices.Values(sub.Pauses), func(a, b models.Pause) int {
+		return a.StartDate.Compare(b.StartDate.Time)
+	})
+
+	var cost float64
+	for month := dates.StartOfMonth(start); !month.After(end); month = month.AddDate(0, 1, 0) {
+		// Дни месяца, попадающие и в подписку, и в период, за вычетом пауз
+		first := later(month, start)
+		last := earlier(dates.EndOfMonth(month), end)
+		days := billableDays(pauses, first, last)
+		if days == 0 {
+			continue
+		}
+
+		if prorate {
+			cost += float64(sub.Price) * float64(days) / float64(dates.DaysInMonth(month))
+		} else {
+			cost += float64(sub.Price)
+		}
+	}
+
+	return cost
+}
+
+// billableDays считает дни в [first, last], не приходящиеся на паузы.
+// Паузы должны быть отсортированы по началу; пересекающиеся паузы
+// вычитаются один раз.
+func billableDays(pauses []models.Pause, first, last time.Time) int {
+	if last.Before(first) {
+		return 0
+	}
+
+	days := daysBetween(first, last) + 1
+	// Первый день, еще не вычтенный ни одной паузой
+	next := first
+	for i := range pauses {
+		pauseStart := later(pauses[i].StartDate.Time, next)
+		pauseEnd := last
+		if pauses[i].EndDate != nil {
+			pauseEnd = earlier(pauses[i].EndDate.Time, last)
+		}
+		if pauseEnd.Before(pauseStart) {
+			continue
+		}
+
+		days -= daysBetween(pauseStart, pauseEnd) + 1
+		next = pauseEnd.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// daysBetween возвращает число дней от a до b (даты в UTC без времени)
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,196 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func date(s string) dates.Date {
+	return dates.NewDate(day(s))
+}
+
+func datePtr(s string) *dates.Date {
+	d := date(s)
+	return &d
+}
+
+func TestPeriodCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      models.Subscription
+		from, to string
+		prorate  bool
+		want     float64
+	}{
+		{
+			name: "full months",
+			sub:  models.Subscription{Price: 300, StartDate: date("2024-01-01")},
+			from: "2024-01-01", to: "2024-03-31",
+			want: 900,
+		},
+		{
+			name: "partial month charged in full without prorate",
+			sub:  models.Subscription{Price: 300, StartDate: date("2024-01-16"), EndDate: datePtr("2024-02-10")},
+			from: "2024-01-01", to: "2024-12-31",
+			want: 600,
+		},
+		{
+			name: "period narrower than the subscription",
+			sub:  models.Subscription{Price: 300, StartDate: date("2023-06-01")},
+			from: "2024-02-10", to: "2024-02-20",
+			want: 300,
+		},
+		{
+			name: "prorated start",
+			sub:  models.Subscription{Price: 310, StartDate: date("2024-01-16")},
+			from: "2024-01-01", to: "2024-01-31",
+			prorate: true,
+			want:    160,
+		},
+		{
+			name: "prorated end",
+			sub:  models.Subscription{Price: 310, StartDate: date("2024-01-01"), EndDate: datePtr("2024-01-10")},
+			from: "2024-01-01", to: "2024-01-31",
+			prorate: true,
+			want:    100,
+		},
+		{
+			name: "prorated february of a leap year",
+			sub:  models.Subscription{Price: 290, StartDate: date("2024-02-15")},
+			from: "2024-02-01", to: "2024-02-29",
+			prorate: true,
+			want:    150,
+		},
+		{
+			name: "prorated february of a common year",
+			sub:  models.Subscription{Price: 280, StartDate: date("2023-02-15")},
+			from: "2023-02-01", to: "2023-02-28",
+			prorate: true,
+			want:    140,
+		},
+		{
+			name: "prorated period across a year boundary",
+			sub:  models.Subscription{Price: 310, StartDate: date("2023-01-01")},
+			from: "2023-12-17", to: "2024-01-15",
+			prorate: true,
+			want:    150 + 150,
+		},
+		{
+			name: "paused whole month is not charged",
+			sub: models.Subscription{Price: 300, StartDate: date("2024-01-01"), Pauses: []models.Pause{
+				{StartDate: date("2024-02-01"), EndDate: datePtr("2024-02-29")},
+			}},
+			from: "2024-01-01", to: "2024-03-31",
+			want: 600,
+		},
+		{
+			name: "partially paused month is charged in full without prorate",
+			sub: models.Subscription{Price: 300, StartDate: date("2024-01-01"), Pauses: []models.Pause{
+				{StartDate: date("2024-02-01"), EndDate: datePtr("2024-02-28")},
+			}},
+			from: "2024-02-01", to: "2024-02-29",
+			want: 300,
+		},
+		{
+			name: "prorated pause",
+			sub: models.Subscription{Price: 310, StartDate: date("2024-01-01"), Pauses: []models.Pause{
+				{StartDate: date("2024-01-11"), EndDate: datePtr("2024-01-20")},
+			}},
+			from: "2024-01-01", to: "2024-01-31",
+			prorate: true,
+			want:    210,
+		},
+		{
+			name: "open pause stops charges",
+			sub: models.Subscription{Price: 310, StartDate: date("2024-01-01"), Pauses: []models.Pause{
+				{StartDate: date("2024-01-21")},
+			}},
+			from: "2024-01-01", to: "2024-03-31",
+			prorate: true,
+			want:    200,
+		},
+		{
+			name: "unsorted overlapping pauses are subtracted once",
+			sub: models.Subscription{Price: 310, StartDate: date("2024-01-01"), Pauses: []models.Pause{
+				{StartDate: date("2024-01-10"), EndDate: datePtr("2024-01-20")},
+				{StartDate: date("2024-01-05"), EndDate: datePtr("2024-01-15")},
+			}},
+			from: "2024-01-01", to: "2024-01-31",
+			prorate: true,
+			want:    150,
+		},
+		{
+			name: "starts after the period",
+			sub:  models.Subscription{Price: 300, StartDate: date("2024-05-01")},
+			from: "2024-01-01", to: "2024-04-30",
+			want: 0,
+		},
+		{
+			name: "ends before the period",
+			sub:  models.Subscription{Price: 300, StartDate: date("2023-01-01"), EndDate: datePtr("2023-12-31")},
+			from: "2024-01-01", to: "2024-04-30",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := periodCost(tt.sub, day(tt.from), day(tt.to), tt.prorate)
+			if got != tt.want {
+				t.Errorf("periodCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillableDays(t *testing.T) {
+	pauses := []models.Pause{
+		{StartDate: date("2024-01-05"), EndDate: datePtr("2024-01-09")},
+		{StartDate: date("2024-01-20")},
+	}
+
+	tests := []struct {
+		name        string
+		first, last string
+		want        int
+	}{
+		{"single day", "2024-01-01", "2024-01-01", 1},
+		{"empty range", "2024-01-02", "2024-01-01", 0},
+		{"before pauses", "2024-01-01", "2024-01-04", 4},
+		{"pause inside the range", "2024-01-01", "2024-01-10", 5},
+		{"range inside a pause", "2024-01-06", "2024-01-08", 0},
+		{"pause edges", "2024-01-09", "2024-01-20", 10},
+		{"open pause to the end", "2024-01-01", "2024-12-31", 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := billableDays(pauses, day(tt.first), day(tt.last)); got != tt.want {
+				t.Errorf("billableDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTotalCostRounds(t *testing.T) {
+	subscriptions := []models.Subscription{
+		{Price: 100, StartDate: date("2024-01-21")},
+		{Price: 100, StartDate: date("2024-01-21")},
+	}
+
+	// 100 * 11/31 ≈ 35.48 за каждую; округляется сумма, а не слагаемые
+	if got := TotalCost(subscriptions, day("2024-01-01"), day("2024-01-31"), true); got != 71 {
+		t.Errorf("TotalCost() = %d, want 71", got)
+	}
+}
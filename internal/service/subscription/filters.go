@@ -0,0 +1,56 @@
+package subscription
+
+import (
+	"strings"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/validation"
+)
+
+// parseFilters разбирает строковые даты фильтров в filters.Dates и проверяет,
+// что границы цены и даты начала не противоречат друг другу. Даты с ошибкой
+// формата уже собраны в verrs по тегам validate и пропускаются.
+//
+// Месяц без дня трактуется целиком: active_at=2025-07 — действует хотя бы
+// день июля, started_after=2025-07 — начало после июля, started_before и
+// ends_before — раньше июля, created_since — с начала июля.
+func (s *subscription) parseFilters(verrs *validation.Errors, filters *models.SubscriptionFilters) {
+	parse := func(field string, value *string, parse func(string) (time.Time, error)) *time.Time {
+		if value == nil || verrs.Has(field) {
+			return nil
+		}
+		t, err := parse(*value)
+		if err != nil {
+			return nil
+		}
+		return &t
+	}
+
+	filters.Dates = models.SubscriptionDateFilters{
+		ActiveFrom:    parse("active_at", filters.ActiveAt, dates.ParseStart),
+		ActiveTo:      parse("active_at", filters.ActiveAt, dates.ParseEnd),
+		StartedAfter:  parse("started_after", filters.StartedAfter, dates.ParseEnd),
+		StartedBefore: parse("started_before", filters.StartedBefore, dates.ParseStart),
+		EndsBefore:    parse("ends_before", filters.EndsBefore, dates.ParseStart),
+		CreatedSince:  parse("created_since", filters.CreatedSince, dates.ParseStart),
+	}
+
+	if after, before := filters.Dates.StartedAfter, filters.Dates.StartedBefore; after != nil && before != nil && !after.Before(*before) {
+		verrs.Add("started_before", validation.CodeDateRange, errs.ErrInvalidDateRange.Error())
+	}
+
+	if filters.PriceMin != nil && filters.PriceMax != nil && *filters.PriceMin > *filters.PriceMax {
+		verrs.Add("price_max", validation.CodePriceRange, "must be greater than or equal to price_min")
+	}
+}
+
+// trimAll обрезает пробелы в каждом значении; nil остается nil
+func trimAll(values []string) []string {
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
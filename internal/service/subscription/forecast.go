@@ -0,0 +1,156 @@
+package subscription
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Forecast прогнозирует расходы по действующим подпискам на req.Months
+// календарных месяцев, начиная с текущего (в нем — списания с сегодняшнего
+// дня). Учитываются даты списаний, даты окончания, автопродление и
+// запланированные изменения цены.
+func (s *subscription) Forecast(ctx context.Context, req *models.ForecastRequest) (_ *models.ForecastResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.Forecast")
+	defer tracing.End(span, &err)
+
+	if req.ServiceName != nil {
+		trimmed := strings.TrimSpace(*req.ServiceName)
+		req.ServiceName = &trimmed
+	}
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+	if req.Months == 0 {
+		req.Months = models.DefaultForecastMonths
+	}
+	span.SetAttributes(attribute.Int("forecast.months", req.Months))
+
+	from := today().Time
+	first, to := forecastHorizon(from, req.Months)
+
+	subscriptions, err := s.repo.GetActiveSubscriptions(ctx, req.UserID, req.ServiceName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(subscriptions))
+	for i, sub := range subscriptions {
+		ids[i] = sub.ID
+	}
+	pending, err := s.repo.GetPendingPriceChanges(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[int][]models.PriceChange, len(pending))
+	for _, change := range pending {
+		changes[change.SubscriptionID] = append(changes[change.SubscriptionID], change)
+	}
+
+	months := make([]serviceTotals, req.Months)
+	total := serviceTotals{}
+	for _, sub := range subscriptions {
+		for _, date := range chargeDates(sub, from, to) {
+			amount := chargeAmount(sub, priceAt(sub.Price, changes[sub.ID], date))
+			month := monthIndex(first, date)
+
+			if months[month] == nil {
+				months[month] = serviceTotals{}
+			}
+			months[month].add(sub.ServiceName, amount)
+			total.add(sub.ServiceName, amount)
+		}
+	}
+
+	response := &models.ForecastResponse{
+		Months:      make([]models.ForecastMonth, req.Months),
+		Services:    total.list(),
+		TotalAmount: total.amount(),
+		StartDate:   dates.NewDate(from),
+		EndDate:     dates.NewDate(to),
+		UserID:      req.UserID,
+		ServiceName: req.ServiceName,
+	}
+	for i, totals := range months {
+		response.Months[i] = models.ForecastMonth{
+			Month:    dates.NewDate(first.AddDate(0, i, 0)),
+			Amount:   totals.amount(),
+			Services: totals.list(),
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("forecast.subscriptions", len(subscriptions)),
+		attribute.Int("forecast.total_amount", response.TotalAmount),
+	)
+	return response, nil
+}
+
+// forecastHorizon возвращает первый день текущего месяца и последний день
+// последнего из months месяцев прогноза
+func forecastHorizon(from time.Time, months int) (first, to time.Time) {
+	first = dates.StartOfMonth(from)
+	return first, dates.EndOfMonth(first.AddDate(0, months-1, 0))
+}
+
+// monthIndex возвращает номер месяца прогноза, на который приходится date;
+// first — первый день первого месяца
+func monthIndex(first, date time.Time) int {
+	return (date.Year()-first.Year())*12 + int(date.Month()-first.Month())
+}
+
+// priceAt возвращает цену списания в date: последнюю из вступивших в силу
+// запланированных цен (changes упорядочены по дате) либо текущую цену
+func priceAt(price int, changes []models.PriceChange, date time.Time) int {
+	for _, change := range changes {
+		if change.EffectiveDate.After(date) {
+			break
+		}
+		price = change.Price
+	}
+	return price
+}
+
+// serviceTotals расходы по названию сервиса
+type serviceTotals map[string]*models.ServiceAmount
+
+func (t serviceTotals) add(serviceName string, amount int) {
+	entry, ok := t[serviceName]
+	if !ok {
+		entry = &models.ServiceAmount{ServiceName: serviceName}
+		t[serviceName] = entry
+	}
+	entry.Amount += amount
+	entry.Charges++
+}
+
+func (t serviceTotals) amount() int {
+	total := 0
+	for _, entry := range t {
+		total += entry.Amount
+	}
+	return total
+}
+
+// list возвращает расходы по сервисам от больших к меньшим
+func (t serviceTotals) list() []models.ServiceAmount {
+	result := make([]models.ServiceAmount, 0, len(t))
+	for _, entry := range t {
+		result = append(result, *entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount != result[j].Amount {
+			return result[i].Amount > result[j].Amount
+		}
+		return result[i].ServiceName < result[j].ServiceName
+	})
+	return result
+}
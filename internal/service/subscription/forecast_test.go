@@ -0,0 +1,153 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func TestPriceAt(t *testing.T) {
+	changes := []models.PriceChange{
+		{EffectiveDate: date("2025-03-01"), Price: 599},
+		{EffectiveDate: date("2025-06-15"), Price: 699},
+		{EffectiveDate: date("2025-06-20"), Price: 649},
+		{EffectiveDate: date("2026-01-01"), Price: 799},
+	}
+
+	tests := []struct {
+		date string
+		want int
+	}{
+		{"2025-02-28", 499},
+		{"2025-03-01", 599},
+		{"2025-06-14", 599},
+		{"2025-06-15", 699},
+		{"2025-06-19", 699},
+		{"2025-06-20", 649},
+		{"2025-12-31", 649},
+		{"2026-01-01", 799},
+		{"2027-05-10", 799},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			if got := priceAt(499, changes, day(tt.date)); got != tt.want {
+				t.Errorf("priceAt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		if got := priceAt(499, nil, day("2025-06-15")); got != 499 {
+			t.Errorf("priceAt() = %d, want 499", got)
+		}
+	})
+}
+
+func TestForecastHorizon(t *testing.T) {
+	tests := []struct {
+		from      string
+		months    int
+		first, to string
+	}{
+		{"2025-01-15", 1, "2025-01-01", "2025-01-31"},
+		{"2025-03-31", 12, "2025-03-01", "2026-02-28"},
+		{"2023-03-10", 12, "2023-03-01", "2024-02-29"},
+		{"2025-12-01", 36, "2025-12-01", "2028-11-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			first, to := forecastHorizon(day(tt.from), tt.months)
+			if !first.Equal(day(tt.first)) || !to.Equal(day(tt.to)) {
+				t.Errorf("forecastHorizon() = %s, %s; want %s, %s",
+					first.Format(time.DateOnly), to.Format(time.DateOnly), tt.first, tt.to)
+			}
+			if got := monthIndex(first, first); got != 0 {
+				t.Errorf("monthIndex(first) = %d, want 0", got)
+			}
+			if got := monthIndex(first, to); got != tt.months-1 {
+				t.Errorf("monthIndex(to) = %d, want %d", got, tt.months-1)
+			}
+		})
+	}
+}
+
+func TestMonthIndex(t *testing.T) {
+	first := day("2025-11-01")
+
+	tests := []struct {
+		date string
+		want int
+	}{
+		{"2025-11-01", 0},
+		{"2025-11-30", 0},
+		{"2025-12-01", 1},
+		{"2026-01-31", 2},
+		{"2026-10-31", 11},
+		{"2028-10-31", 35},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			if got := monthIndex(first, day(tt.date)); got != tt.want {
+				t.Errorf("monthIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Списания на краю горизонта попадают в последний месяц прогноза
+func TestForecastChargesWithinHorizon(t *testing.T) {
+	tests := []struct {
+		name   string
+		sub    models.Subscription
+		from   string
+		months int
+		last   string
+	}{
+		{
+			name:   "monthly on last day",
+			sub:    models.Subscription{StartDate: date("2024-01-31"), BillingPeriod: models.BillingMonthly},
+			from:   "2025-03-31",
+			months: 12,
+			last:   "2026-02-28",
+		},
+		{
+			name:   "yearly on horizon end",
+			sub:    models.Subscription{StartDate: date("2024-02-29"), BillingPeriod: models.BillingYearly},
+			from:   "2025-03-01",
+			months: 12,
+			last:   "2026-02-28",
+		},
+		{
+			name:   "quarterly",
+			sub:    models.Subscription{StartDate: date("2025-01-31"), BillingPeriod: models.BillingQuarterly},
+			from:   "2025-01-31",
+			months: 4,
+			last:   "2025-04-30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, to := forecastHorizon(day(tt.from), tt.months)
+			charges := chargeDates(tt.sub, day(tt.from), to)
+			if len(charges) == 0 {
+				t.Fatal("chargeDates() returned no charges")
+			}
+			for _, charge := range charges {
+				if month := monthIndex(first, charge); month < 0 || month >= tt.months {
+					t.Errorf("monthIndex(%s) = %d, outside [0, %d)", charge.Format(time.DateOnly), month, tt.months)
+				}
+			}
+			if last := charges[len(charges)-1]; !last.Equal(day(tt.last)) {
+				t.Errorf("last charge = %s, want %s", last.Format(time.DateOnly), tt.last)
+			}
+			if got := monthIndex(first, charges[len(charges)-1]); got != tt.months-1 {
+				t.Errorf("last charge month = %d, want %d", got, tt.months-1)
+			}
+		})
+	}
+}
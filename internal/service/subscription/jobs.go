@@ -0,0 +1,91 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Сколько подписок продлевается за один проход
+const renewBatchSize = 100
+
+// ExpireSubscriptions фиксирует статус подписок, срок которых истек
+func (s *subscription) ExpireSubscriptions(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.ExpireSubscriptions")
+	defer tracing.End(span, &err)
+
+	events, err := s.repo.ExpireSubscriptions(ctx, today().Time)
+	if err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("subscription.expired", len(events)))
+	return len(events), nil
+}
+
+// ApplyPriceChanges записывает в подписки запланированные цены, вступившие в силу
+func (s *subscription) ApplyPriceChanges(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.ApplyPriceChanges")
+	defer tracing.End(span, &err)
+
+	count, err := s.repo.ApplyPriceChanges(ctx, today().Time)
+	if err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("subscription.repriced", count))
+	return count, nil
+}
+
+// RenewSubscriptions продлевает подписки с автопродлением, срок которых истек:
+// дата окончания переносится на целое число месяцев так, чтобы подписка
+// снова действовала сегодня
+func (s *subscription) RenewSubscriptions(ctx context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.RenewSubscriptions")
+	defer tracing.End(span, &err)
+
+	now := today().Time
+	total := 0
+	for {
+		due, err := s.repo.GetDueRenewals(ctx, now, renewBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		renewed := 0
+		for _, sub := range due {
+			event, err := s.repo.RenewSubscription(ctx, sub.ID, *sub.EndDate, renewedEndDate(*sub.EndDate, now, sub.BillingPeriod.Months()))
+			if err != nil {
+				return total, err
+			}
+			if event != nil {
+				renewed++
+			}
+		}
+		total += renewed
+
+		// Неполная пачка — продлевать больше нечего; пачка без продлений
+		// означает, что подписки меняются параллельно, и проход завершается
+		if len(due) < renewBatchSize || renewed == 0 {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("subscription.renewed", total))
+	return total, nil
+}
+
+// renewedEndDate возвращает первую дату окончания не раньше today, полученную
+// продлением end на целое число периодов по step месяцев
+func renewedEndDate(end dates.Date, today time.Time, step int) dates.Date {
+	months := step
+	next := dates.AddMonths(end.Time, months)
+	for next.Before(today) {
+		months += step
+		next = dates.AddMonths(end.Time, months)
+	}
+	return dates.NewDate(next)
+}
@@ -0,0 +1,27 @@
+package subscription
+
+import "testing"
+
+func TestRenewedEndDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		end, today string
+		step       int
+		want       string
+	}{
+		{"monthly", "2024-01-31", "2024-02-10", 1, "2024-02-29"},
+		{"monthly catches up", "2024-01-15", "2024-04-20", 1, "2024-05-15"},
+		{"quarterly", "2024-01-31", "2024-02-10", 3, "2024-04-30"},
+		{"quarterly catches up", "2024-01-15", "2024-08-01", 3, "2024-10-15"},
+		{"yearly from february 29", "2024-02-29", "2024-03-01", 12, "2025-02-28"},
+		{"due today", "2024-01-15", "2024-04-15", 3, "2024-04-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renewedEndDate(date(tt.end), day(tt.today), tt.step); !got.Equal(day(tt.want)) {
+				t.Errorf("renewedEndDate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
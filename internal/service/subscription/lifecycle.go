@@ -0,0 +1,142 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Действия над подпиской
+const (
+	actionPause  = "pause"
+	actionResume = "resume"
+	actionCancel = "cancel"
+)
+
+// transitions допустимые переходы статусов: действие -> текущий статус -> новый.
+// cancelled и expired — конечные статусы.
+var transitions = map[string]map[models.Status]models.Status{
+	actionPause: {
+		models.StatusActive: models.StatusPaused,
+	},
+	actionResume: {
+		models.StatusPaused: models.StatusActive,
+	},
+	actionCancel: {
+		models.StatusActive: models.StatusCancelled,
+		models.StatusPaused: models.StatusCancelled,
+	},
+}
+
+// nextStatus возвращает статус после действия или TransitionError
+func nextStatus(action string, current models.Status) (models.Status, error) {
+	next, ok := transitions[action][current]
+	if !ok {
+		return "", &errs.TransitionError{Action: action, Status: string(current)}
+	}
+	return next, nil
+}
+
+// today возвращает текущую дату (UTC)
+func today() dates.Date {
+	return dates.NewDate(time.Now().UTC())
+}
+
+func (s *subscription) PauseSubscription(ctx context.Context, id int) (_ *models.Subscription, err error) {
+	return s.changeStatus(ctx, "subscription.PauseSubscription", id, actionPause, func(sub *models.Subscription, change *models.StatusChange) error {
+		// Пауза начинается сегодня; отмена в конце периода сохраняется
+		start := today()
+		change.PauseStart = &start
+		change.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+		return nil
+	})
+}
+
+func (s *subscription) ResumeSubscription(ctx context.Context, id int) (_ *models.Subscription, err error) {
+	return s.changeStatus(ctx, "subscription.ResumeSubscription", id, actionResume, func(sub *models.Subscription, change *models.StatusChange) error {
+		// Сегодня подписка снова оплачивается, последний день паузы — вчера
+		lastPauseDay := dates.NewDate(today().AddDate(0, 0, -1))
+		change.LastPauseDay = &lastPauseDay
+		change.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+		return nil
+	})
+}
+
+func (s *subscription) CancelSubscription(ctx context.Context, id int, req *models.CancelSubscriptionRequest) (_ *models.Subscription, err error) {
+	return s.changeStatus(ctx, "subscription.CancelSubscription", id, actionCancel, func(sub *models.Subscription, change *models.StatusChange) error {
+		now := time.Now()
+		change.CancelledAt = &now
+
+		if !req.AtPeriodEnd {
+			// Немедленная отмена: сегодня последний день подписки
+			endDate := today()
+			change.EndDate = earliestEndDate(sub.EndDate, endDate)
+			change.LastPauseDay = &endDate
+			return nil
+		}
+
+		// Отмена в конце периода допустима только для действующей подписки:
+		// она остается active до конца текущего месяца
+		if sub.Status != models.StatusActive {
+			return &errs.TransitionError{Action: actionCancel, Status: string(sub.Status)}
+		}
+		change.To = models.StatusActive
+		change.CancelAtPeriodEnd = true
+		change.EndDate = earliestEndDate(sub.EndDate, dates.NewDate(dates.EndOfMonth(today().Time)))
+		return nil
+	})
+}
+
+// earliestEndDate не дает отмене продлить подписку дальше уже заданной даты окончания
+func earliestEndDate(current *dates.Date, candidate dates.Date) *dates.Date {
+	if current != nil && current.Before(candidate.Time) {
+		return current
+	}
+	return &candidate
+}
+
+// changeStatus проверяет переход по таблице transitions, дополняет изменение
+// через build и атомарно применяет его в репозитории
+func (s *subscription) changeStatus(ctx context.Context, spanName string, id int, action string, build func(*models.Subscription, *models.StatusChange) error) (_ *models.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, spanName)
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", id))
+
+	if id <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := nextStatus(action, sub.Status)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("subscription.status.from", string(sub.Status)),
+		attribute.String("subscription.status.to", string(next)),
+	)
+
+	change := &models.StatusChange{From: sub.Status, To: next}
+	if err := build(sub, change); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.ChangeStatus(ctx, id, change)
+	if errors.Is(err, errs.ErrInvalidStatusTransition) {
+		// Статус изменился параллельно — сообщаем клиенту актуальный
+		if current, getErr := s.repo.GetSubscriptionByID(ctx, id); getErr == nil {
+			return nil, &errs.TransitionError{Action: action, Status: string(current.Status)}
+		}
+	}
+	return updated, err
+}
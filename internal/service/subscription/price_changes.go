@@ -0,0 +1,84 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/IceMAN2377/market/internal/validation"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SchedulePriceChange планирует новую цену подписки с даты effective_date.
+// Дата без дня означает первый день месяца.
+func (s *subscription) SchedulePriceChange(ctx context.Context, subscriptionID int, req *models.SchedulePriceChangeRequest) (_ *models.PriceChange, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.SchedulePriceChange")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", subscriptionID))
+
+	if subscriptionID <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	verrs := s.validator.Struct(req)
+	effective, _ := dates.ParseStart(req.EffectiveDate)
+	if !verrs.Has("effective_date") && !effective.After(today().Time) {
+		verrs.Add("effective_date", validation.CodeFutureDate, "must be a date after today")
+	}
+	if err := verrs.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreatePriceChange(ctx, &models.PriceChange{
+		SubscriptionID: subscriptionID,
+		EffectiveDate:  dates.NewDate(effective),
+		Price:          req.Price,
+	})
+}
+
+// GetPriceChanges возвращает запланированные и примененные изменения цены подписки
+func (s *subscription) GetPriceChanges(ctx context.Context, subscriptionID int) (_ *models.PriceChangeListResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.GetPriceChanges")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", subscriptionID))
+
+	if subscriptionID <= 0 {
+		return nil, errs.ErrInvalidData
+	}
+
+	if _, err := s.repo.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.GetPriceChanges(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []models.PriceChange{}
+	}
+
+	return &models.PriceChangeListResponse{PriceChanges: changes}, nil
+}
+
+// DeletePriceChange отменяет еще не примененное изменение цены
+func (s *subscription) DeletePriceChange(ctx context.Context, subscriptionID, id int) (err error) {
+	ctx, span := tracer.Start(ctx, "subscription.DeletePriceChange")
+	defer tracing.End(span, &err)
+	span.SetAttributes(
+		attribute.Int("subscription.id", subscriptionID),
+		attribute.Int("price_change.id", id),
+	)
+
+	if subscriptionID <= 0 || id <= 0 {
+		return errs.ErrInvalidData
+	}
+
+	return s.repo.DeletePriceChange(ctx, subscriptionID, id)
+}
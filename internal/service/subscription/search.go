@@ -0,0 +1,75 @@
+package subscription
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+)
+
+// Search ищет подписки по названию сервиса, заметкам и меткам с учетом
+// опечаток; результаты отсортированы по убыванию релевантности
+func (s *subscription) Search(ctx context.Context, req *models.SearchRequest) (_ *models.SearchResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.Search")
+	defer tracing.End(span, &err)
+
+	req.Query = strings.TrimSpace(req.Query)
+
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.SearchSubscriptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.SearchResult{}
+	}
+	for i := range results {
+		escapeHighlights(&results[i].Highlights)
+	}
+
+	return &models.SearchResponse{
+		Results: results,
+		Query:   req.Query,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// highlightMarkers заменяет маркеры репозитория на HTML разметку
+var highlightMarkers = strings.NewReplacer(
+	models.MatchStart, models.HighlightStart,
+	models.MatchStop, models.HighlightStop,
+)
+
+// escapeHighlights экранирует пользовательский текст фрагментов как HTML,
+// чтобы разметкой в них были только маркеры <mark>...</mark>
+func escapeHighlights(h *models.SearchHighlights) {
+	h.ServiceName = highlightHTML(h.ServiceName)
+	if h.Notes != nil {
+		notes := highlightHTML(*h.Notes)
+		h.Notes = &notes
+	}
+	for i, tag := range h.Tags {
+		h.Tags[i] = html.EscapeString(tag)
+	}
+}
+
+// highlightHTML экранирует текст и заменяет маркеры совпадений на <mark>
+func highlightHTML(s string) string {
+	return highlightMarkers.Replace(html.EscapeString(s))
+}
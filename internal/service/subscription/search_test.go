@@ -0,0 +1,63 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func (r *fakeRepository) SearchSubscriptions(ctx context.Context, req *models.SearchRequest) ([]models.SearchResult, error) {
+	return r.results, nil
+}
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Netflix", "Netflix"},
+		{"match", "\x02Netflix\x03 Premium", "<mark>Netflix</mark> Premium"},
+		{"markup in text", "<img src=x onerror=\"alert(1)\"> \x02family\x03", "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>family</mark>"},
+		{"mark in text", "<mark>fake</mark> \x02real\x03", "&lt;mark&gt;fake&lt;/mark&gt; <mark>real</mark>"},
+		{"ampersand", "\x02AT&T\x03 & co", "<mark>AT&amp;T</mark> &amp; co"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightHTML(tt.in); got != tt.want {
+				t.Errorf("highlightHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchEscapesHighlights(t *testing.T) {
+	notes := "\x02family\x03 <script>alert(1)</script>"
+	repo := &fakeRepository{results: []models.SearchResult{{
+		Highlights: models.SearchHighlights{
+			ServiceName: "\x02Netflix\x03 <b>",
+			Notes:       &notes,
+			Tags:        []string{"a&b"},
+		},
+	}}}
+	svc := NewService(repo, dates.Bounds{MinYear: 2000, MaxYearsAhead: 10}, nil)
+
+	resp, err := svc.Search(context.Background(), &models.SearchRequest{Query: "family"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	h := resp.Results[0].Highlights
+	if want := "<mark>Netflix</mark> &lt;b&gt;"; h.ServiceName != want {
+		t.Errorf("service_name = %q, want %q", h.ServiceName, want)
+	}
+	if want := "<mark>family</mark> &lt;script&gt;alert(1)&lt;/script&gt;"; h.Notes == nil || *h.Notes != want {
+		t.Errorf("notes = %v, want %q", h.Notes, want)
+	}
+	if want := "a&amp;b"; len(h.Tags) != 1 || h.Tags[0] != want {
+		t.Errorf("tags = %v, want [%q]", h.Tags, want)
+	}
+}
@@ -2,49 +2,41 @@ package subscription
 
 import (
 	"context"
+	"github.com/IceMAN2377/market/internal/dates"
 	errs "github.com/IceMAN2377/market/internal/errors"
 	"github.com/IceMAN2377/market/internal/models"
 	"github.com/IceMAN2377/market/internal/repository"
 	"github.com/IceMAN2377/market/internal/service"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/IceMAN2377/market/internal/validation"
 	"github.com/google/uuid"
-	"regexp"
-	"strconv"
+	"go.opentelemetry.io/otel/attribute"
 	"strings"
 	"time"
 )
 
-type subscription struct {
-	repo repository.Repository
+// BudgetChecker проверяет, не превысит ли новая или измененная подписка жесткий
+// бюджет пользователя; при превышении возвращает *errors.BudgetExceededError
+type BudgetChecker interface {
+	CheckSubscription(ctx context.Context, sub *models.Subscription) error
 }
 
-func NewService(repo repository.Repository) service.Service {
-	return &subscription{
-		repo: repo,
-	}
+type subscription struct {
+	repo      repository.Repository
+	validator *validation.Validator
+	budgets   BudgetChecker
 }
 
-// validateDateFormat проверяет формат даты MM-YYYY
-func (s *subscription) validateDateFormat(date string) error {
-	matched, _ := regexp.MatchString(`^(0[1-9]|1[0-2])-\d{4}$`, date)
-	if !matched {
-		return errs.ErrInvalidDateFormat
-	}
-
-	// Дополнительная проверка - парсим дату
-	parts := strings.Split(date, "-")
-	month, _ := strconv.Atoi(parts[0])
-	year, _ := strconv.Atoi(parts[1])
-
-	if month < 1 || month > 12 {
-		return errs.ErrInvalidDateFormat
-	}
+var tracer = tracing.Tracer("github.com/IceMAN2377/market/internal/service/subscription")
 
-	currentYear := time.Now().Year()
-	if year < 2000 || year > currentYear+10 {
-		return errs.ErrInvalidDateFormat
+// NewService создает сервис подписок. bounds задает допустимый диапазон
+// лет во входных датах; budgets может быть nil, тогда бюджеты не проверяются.
+func NewService(repo repository.Repository, bounds dates.Bounds, budgets BudgetChecker) service.Service {
+	return &subscription{
+		repo:      repo,
+		validator: validation.New(bounds),
+		budgets:   budgets,
 	}
-
-	return nil
 }
 
 // validateUUID проверяет корректность UUID
@@ -55,71 +47,107 @@ func (s *subscription) validateUUID(uuidStr string) error {
 	return nil
 }
 
-// validateDateRange проверяет корректность диапазона дат
-func (s *subscription) validateDateRange(startDate, endDate string) error {
-	if err := s.validateDateFormat(startDate); err != nil {
-		return err
+// parsePeriod разбирает даты начала и окончания (окончание включительно:
+// для месяца без дня — его последний день). Ошибки формата уже собраны
+// по тегам validate; здесь добавляется нарушение диапазона, если начало
+// позже окончания.
+func (s *subscription) parsePeriod(verrs *validation.Errors, startDate, endDate string) (start, end time.Time) {
+	if verrs.Has("start_date") || verrs.Has("end_date") {
+		return start, end
 	}
 
-	if err := s.validateDateFormat(endDate); err != nil {
-		return err
+	start, _ = dates.ParseStart(startDate)
+	end, _ = dates.ParseEnd(endDate)
+	if start.After(end) {
+		verrs.Add("end_date", validation.CodeDateRange, errs.ErrInvalidDateRange.Error())
 	}
+	return start, end
+}
 
-	// Сравниваем даты (простое строковое сравнение работает для формата MM-YYYY)
-	startParts := strings.Split(startDate, "-")
-	endParts := strings.Split(endDate, "-")
-
-	startYear, _ := strconv.Atoi(startParts[1])
-	startMonth, _ := strconv.Atoi(startParts[0])
-	endYear, _ := strconv.Atoi(endParts[1])
-	endMonth, _ := strconv.Atoi(endParts[0])
+func (s *subscription) CreateSubscription(ctx context.Context, req *models.CreateSubscriptionRequest) (_ *models.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.CreateSubscription")
+	defer tracing.End(span, &err)
 
-	startTime := time.Date(startYear, time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC)
-	endTime := time.Date(endYear, time.Month(endMonth), 1, 0, 0, 0, 0, time.UTC)
+	req.ServiceName = strings.TrimSpace(req.ServiceName)
+	req.Category = trimCategory(req.Category)
+	req.Tags = normalizeTags(req.Tags)
 
-	if startTime.After(endTime) {
-		return errs.ErrInvalidDateRange
+	// Валидация по тегам validate и диапазона дат: собираем все нарушения сразу
+	verrs := s.validator.Struct(req)
+	start, _ := dates.ParseStart(req.StartDate)
+	var endDate *dates.Date
+	if req.EndDate != nil {
+		var end time.Time
+		start, end = s.parsePeriod(verrs, req.StartDate, *req.EndDate)
+		date := dates.NewDate(end)
+		endDate = &date
 	}
 
-	return nil
-}
-
-func (s *subscription) CreateSubscription(ctx context.Context, req *models.CreateSubscriptionRequest) (*models.Subscription, error) {
-	// Валидация UUID пользователя
-	if err := s.validateUUID(req.UserID); err != nil {
+	// Сопоставление с каталогом: каноническое название и цена по умолчанию
+	var catalogService *models.Service
+	if !verrs.Has("service_name") {
+		var err error
+		if catalogService, err = s.repo.MatchService(ctx, req.ServiceName); err != nil {
+			return nil, err
+		}
+	}
+	price := req.Price
+	if price == 0 && catalogService != nil && catalogService.DefaultPrice != nil {
+		price = *catalogService.DefaultPrice
+	}
+	if price == 0 && !verrs.Has("price") {
+		verrs.Add("price", "required", "is required")
+	}
+	if err := verrs.Err(); err != nil {
 		return nil, err
 	}
 
-	// Валидация формата начальной даты
-	if err := s.validateDateFormat(req.StartDate); err != nil {
-		return nil, err
+	billingPeriod := req.BillingPeriod
+	if billingPeriod == "" {
+		billingPeriod = models.BillingMonthly
 	}
 
-	// Валидация конечной даты, если она указана
-	if req.EndDate != nil {
-		if err := s.validateDateRange(req.StartDate, *req.EndDate); err != nil {
-			return nil, err
+	// Создание модели подписки
+	subscription := &models.Subscription{
+		ServiceName:   req.ServiceName,
+		Price:         price,
+		UserID:        req.UserID,
+		StartDate:     dates.NewDate(start),
+		EndDate:       endDate,
+		AutoRenew:     req.AutoRenew,
+		BillingPeriod: billingPeriod,
+		Category:      req.Category,
+		Tags:          req.Tags,
+		Notes:         req.Notes,
+	}
+	if catalogService != nil {
+		subscription.ServiceName = catalogService.Name
+		subscription.ServiceID = &catalogService.ID
+		if subscription.Category == nil {
+			subscription.Category = catalogService.Category
 		}
 	}
 
-	// Валидация цены
-	if req.Price <= 0 {
-		return nil, errs.ErrInvalidPrice
+	if !req.AllowOverlap {
+		if err := s.checkOverlap(ctx, subscription); err != nil {
+			return nil, err
+		}
 	}
 
-	// Создание модели подписки
-	subscription := &models.Subscription{
-		ServiceName: strings.TrimSpace(req.ServiceName),
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
+	if s.budgets != nil && !req.SkipBudgetCheck {
+		if err := s.budgets.CheckSubscription(ctx, subscription); err != nil {
+			return nil, err
+		}
 	}
 
 	return s.repo.CreateSubscription(ctx, subscription)
 }
 
-func (s *subscription) GetSubscriptionByID(ctx context.Context, id int) (*models.Subscription, error) {
+func (s *subscription) GetSubscriptionByID(ctx context.Context, id int) (_ *models.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.GetSubscriptionByID")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", id))
+
 	if id <= 0 {
 		return nil, errs.ErrInvalidData
 	}
@@ -127,7 +155,10 @@ func (s *subscription) GetSubscriptionByID(ctx context.Context, id int) (*models
 	return s.repo.GetSubscriptionByID(ctx, id)
 }
 
-func (s *subscription) GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) (*models.SubscriptionListResponse, error) {
+func (s *subscription) GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) (_ *models.SubscriptionListResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.GetSubscriptions")
+	defer tracing.End(span, &err)
+
 	// Валидация параметров пагинации
 	if filters.Limit <= 0 {
 		filters.Limit = 10
@@ -139,12 +170,25 @@ func (s *subscription) GetSubscriptions(ctx context.Context, filters *models.Sub
 		filters.Offset = 0
 	}
 
-	// Валидация UUID пользователя, если указан
-	if filters.UserID != nil {
-		if err := s.validateUUID(*filters.UserID); err != nil {
-			return nil, err
+	filters.ServiceNames = trimAll(filters.ServiceNames)
+	filters.Category = trimCategory(filters.Category)
+	filters.Tags = normalizeTags(filters.Tags)
+	if filters.TagMatch == "" {
+		filters.TagMatch = TagMatchAny
+	}
+
+	// Валидация фильтров по тегам validate и UUID пользователей, затем разбор
+	// дат и проверка согласованности границ
+	verrs := s.validator.Struct(filters)
+	for _, userID := range filters.UserIDs {
+		if s.validateUUID(userID) != nil && !verrs.Has("user_id") {
+			verrs.Add("user_id", "uuid", "must be a valid UUID")
 		}
 	}
+	s.parseFilters(verrs, filters)
+	if err := verrs.Err(); err != nil {
+		return nil, err
+	}
 
 	// Получение подписок
 	subscriptions, err := s.repo.GetSubscriptions(ctx, filters)
@@ -163,39 +207,156 @@ func (s *subscription) GetSubscriptions(ctx context.Context, filters *models.Sub
 	return response, nil
 }
 
-func (s *subscription) UpdateSubscription(ctx context.Context, id int, req *models.UpdateSubscriptionRequest) (*models.Subscription, error) {
+func (s *subscription) UpdateSubscription(ctx context.Context, id int, req *models.UpdateSubscriptionRequest) (_ *models.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.UpdateSubscription")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", id))
+
 	if id <= 0 {
 		return nil, errs.ErrInvalidData
 	}
 
+	// Обрезаем пробелы в названии сервиса
+	if req.ServiceName != nil {
+		trimmed := strings.TrimSpace(*req.ServiceName)
+		req.ServiceName = &trimmed
+	}
+	req.Category = trimCategory(req.Category)
+	req.Tags = normalizeTags(req.Tags)
+
+	verrs := s.validator.Struct(req)
+	if err := verrs.Err(); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, что подписка существует
 	existing, err := s.repo.GetSubscriptionByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Валидация конечной даты, если она обновляется
+	// Новое название сопоставляется с каталогом так же, как при создании
+	if req.ServiceName != nil {
+		catalogService, err := s.repo.MatchService(ctx, *req.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		req.ServiceID = nil
+		if catalogService != nil {
+			req.ServiceName = &catalogService.Name
+			req.ServiceID = &catalogService.ID
+		}
+	}
+
+	// Подписка после обновления — для проверки пересечений и бюджетов
+	updated := *existing
+	if req.ServiceName != nil {
+		updated.ServiceName = *req.ServiceName
+		updated.ServiceID = req.ServiceID
+	}
+	if req.Price != nil {
+		updated.Price = *req.Price
+	}
+	if req.AutoRenew != nil {
+		updated.AutoRenew = *req.AutoRenew
+	}
+	if req.BillingPeriod != nil {
+		updated.BillingPeriod = *req.BillingPeriod
+	}
+	if req.Category != nil {
+		updated.Category = nil
+		if *req.Category != "" {
+			updated.Category = req.Category
+		}
+	}
+
+	// Проверка конечной даты относительно даты начала, если она обновляется.
+	// В репозиторий передается дата окончания в ISO формате с учетом точности ввода.
 	if req.EndDate != nil {
-		if err := s.validateDateRange(existing.StartDate, *req.EndDate); err != nil {
+		_, end := s.parsePeriod(verrs, existing.StartDate.String(), *req.EndDate)
+		if err := verrs.Err(); err != nil {
 			return nil, err
 		}
+		endDate := dates.NewDate(end)
+		updated.EndDate = &endDate
+		iso := endDate.String()
+		req.EndDate = &iso
 	}
 
-	// Валидация цены, если она обновляется
-	if req.Price != nil && *req.Price <= 0 {
-		return nil, errs.ErrInvalidPrice
+	// Пересечения проверяются, только если меняется сервис или период:
+	// обновление цены не должно упираться в уже существующие пересечения
+	periodChanged := req.ServiceName != nil || req.EndDate != nil || req.AutoRenew != nil
+	if periodChanged && !req.AllowOverlap {
+		if err := s.checkOverlap(ctx, &updated); err != nil {
+			return nil, err
+		}
 	}
 
-	// Обрезаем пробелы в названии сервиса
-	if req.ServiceName != nil {
-		trimmed := strings.TrimSpace(*req.ServiceName)
-		req.ServiceName = &trimmed
+	// Бюджеты проверяются, если меняется цена, период, периодичность списаний
+	// или область бюджета (сервис, категория); изменение, не увеличивающее
+	// расходы, проходит
+	budgetChanged := periodChanged || req.Price != nil || req.BillingPeriod != nil || req.Category != nil
+	if budgetChanged && s.budgets != nil && !req.SkipBudgetCheck {
+		if err := s.budgets.CheckSubscription(ctx, &updated); err != nil {
+			return nil, err
+		}
 	}
 
 	return s.repo.UpdateSubscription(ctx, id, req)
 }
 
-func (s *subscription) DeleteSubscription(ctx context.Context, id int) error {
+// checkOverlap возвращает *errors.OverlapError, если период подписки пересекается
+// с другими подписками того же пользователя на тот же сервис
+func (s *subscription) checkOverlap(ctx context.Context, sub *models.Subscription) error {
+	ids, err := s.repo.FindOverlaps(ctx, sub)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		return &errs.OverlapError{SubscriptionIDs: ids}
+	}
+	return nil
+}
+
+// GetOverlaps возвращает пары пересекающихся подписок для очистки данных
+func (s *subscription) GetOverlaps(ctx context.Context, filters *models.OverlapFilters) (_ *models.OverlapListResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.GetOverlaps")
+	defer tracing.End(span, &err)
+
+	if filters.Limit <= 0 {
+		filters.Limit = 10
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	if err := s.validator.Struct(filters).Err(); err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.repo.GetOverlaps(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps == nil {
+		overlaps = []models.SubscriptionOverlap{}
+	}
+
+	return &models.OverlapListResponse{
+		Overlaps: overlaps,
+		Limit:    filters.Limit,
+		Offset:   filters.Offset,
+	}, nil
+}
+
+func (s *subscription) DeleteSubscription(ctx context.Context, id int) (err error) {
+	ctx, span := tracer.Start(ctx, "subscription.DeleteSubscription")
+	defer tracing.End(span, &err)
+	span.SetAttributes(attribute.Int("subscription.id", id))
+
 	if id <= 0 {
 		return errs.ErrInvalidData
 	}
@@ -203,31 +364,36 @@ func (s *subscription) DeleteSubscription(ctx context.Context, id int) error {
 	return s.repo.DeleteSubscription(ctx, id)
 }
 
-func (s *subscription) CalculateCost(ctx context.Context, req *models.CostCalculationRequest) (*models.CostCalculationResponse, error) {
-	// Валидация диапазона дат
-	if err := s.validateDateRange(req.StartDate, req.EndDate); err != nil {
-		return nil, err
-	}
+func (s *subscription) CalculateCost(ctx context.Context, req *models.CostCalculationRequest) (_ *models.CostCalculationResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.CalculateCost")
+	defer tracing.End(span, &err)
 
-	// Валидация UUID пользователя, если указан
-	if req.UserID != nil {
-		if err := s.validateUUID(*req.UserID); err != nil {
-			return nil, err
-		}
+	// Валидация по тегам validate и диапазона дат
+	verrs := s.validator.Struct(req)
+	from, to := s.parsePeriod(verrs, req.StartDate, req.EndDate)
+	if err := verrs.Err(); err != nil {
+		return nil, err
 	}
+	span.SetAttributes(attribute.Bool("cost.prorate", req.Prorate))
 
-	// Расчет стоимости
-	totalCost, err := s.repo.CalculateCost(ctx, req)
+	// Подписки, пересекающиеся с периодом
+	subscriptions, err := s.repo.GetSubscriptionsForPeriod(ctx, req, from, to)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &models.CostCalculationResponse{
-		TotalCost:   totalCost,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
+		TotalCost:   TotalCost(subscriptions, from, to, req.Prorate),
+		StartDate:   dates.NewDate(from),
+		EndDate:     dates.NewDate(to),
+		Prorated:    req.Prorate,
 		UserID:      req.UserID,
 		ServiceName: req.ServiceName,
+		ServiceID:   req.ServiceID,
+	}
+	if req.GroupBy != "" {
+		response.GroupBy = req.GroupBy
+		response.Groups = costGroups(subscriptions, from, to, req.Prorate, req.GroupBy)
 	}
 
 	return response, nil
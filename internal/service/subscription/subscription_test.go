@@ -0,0 +1,123 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/repository"
+)
+
+// fakeRepository хранит одну подписку и результаты поиска; остальные методы
+// не используются
+type fakeRepository struct {
+	repository.Repository
+	sub     models.Subscription
+	updates *models.UpdateSubscriptionRequest
+	results []models.SearchResult
+}
+
+func (r *fakeRepository) GetSubscriptionByID(ctx context.Context, id int) (*models.Subscription, error) {
+	sub := r.sub
+	return &sub, nil
+}
+
+func (r *fakeRepository) UpdateSubscription(ctx context.Context, id int, updates *models.UpdateSubscriptionRequest) (*models.Subscription, error) {
+	r.updates = updates
+	sub := r.sub
+	return &sub, nil
+}
+
+// fakeBudgets запоминает подписки, переданные на проверку
+type fakeBudgets struct {
+	checked []models.Subscription
+}
+
+func (b *fakeBudgets) CheckSubscription(ctx context.Context, sub *models.Subscription) error {
+	b.checked = append(b.checked, *sub)
+	return nil
+}
+
+func TestUpdateSubscriptionBudgetCheck(t *testing.T) {
+	yearly := models.BillingYearly
+	price := 599
+	notes := "moved to a family plan"
+
+	tests := []struct {
+		name   string
+		req    models.UpdateSubscriptionRequest
+		check  bool
+		period models.BillingPeriod
+		price  int
+	}{
+		{
+			name:   "billing period only",
+			req:    models.UpdateSubscriptionRequest{BillingPeriod: &yearly},
+			check:  true,
+			period: models.BillingYearly,
+			price:  499,
+		},
+		{
+			name:   "billing period and price",
+			req:    models.UpdateSubscriptionRequest{BillingPeriod: &yearly, Price: &price},
+			check:  true,
+			period: models.BillingYearly,
+			price:  599,
+		},
+		{
+			name:   "price keeps billing period",
+			req:    models.UpdateSubscriptionRequest{Price: &price},
+			check:  true,
+			period: models.BillingMonthly,
+			price:  599,
+		},
+		{
+			name: "notes only",
+			req:  models.UpdateSubscriptionRequest{Notes: &notes},
+		},
+		{
+			name: "skipped",
+			req:  models.UpdateSubscriptionRequest{BillingPeriod: &yearly, SkipBudgetCheck: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{sub: models.Subscription{
+				ID:            1,
+				ServiceName:   "Netflix",
+				Price:         499,
+				UserID:        "123e4567-e89b-12d3-a456-426614174000",
+				StartDate:     date("2024-01-15"),
+				Status:        models.StatusActive,
+				BillingPeriod: models.BillingMonthly,
+			}}
+			budgets := &fakeBudgets{}
+			svc := NewService(repo, dates.Bounds{MinYear: 2000, MaxYearsAhead: 10}, budgets)
+
+			req := tt.req
+			if _, err := svc.UpdateSubscription(context.Background(), 1, &req); err != nil {
+				t.Fatalf("UpdateSubscription() error = %v", err)
+			}
+			if repo.updates == nil {
+				t.Fatal("repository update was not called")
+			}
+
+			if !tt.check {
+				if len(budgets.checked) != 0 {
+					t.Errorf("CheckSubscription() called %d times, want none", len(budgets.checked))
+				}
+				return
+			}
+			if len(budgets.checked) != 1 {
+				t.Fatalf("CheckSubscription() called %d times, want once", len(budgets.checked))
+			}
+			checked := budgets.checked[0]
+			if checked.BillingPeriod != tt.period || checked.Price != tt.price {
+				t.Errorf("checked billing period %q, price %d; want %q, %d",
+					checked.BillingPeriod, checked.Price, tt.period, tt.price)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package subscription
+
+import (
+	"slices"
+	"strings"
+)
+
+// Режимы фильтрации по меткам
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// normalizeTags приводит метки к нижнему регистру без пробелов по краям,
+// сортирует и убирает повторы. nil остается nil: для обновления это значит
+// «метки не меняются», а пустой список — «удалить все метки».
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, len(tags))
+	for i, tag := range tags {
+		normalized[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// trimCategory обрезает пробелы в названии категории
+func trimCategory(category *string) *string {
+	if category == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*category)
+	return &trimmed
+}
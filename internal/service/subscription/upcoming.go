@@ -0,0 +1,123 @@
+package subscription
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UpcomingCharges возвращает ближайшие списания по действующим подпискам,
+// попадающие в окно от сегодня до сегодня плюс req.Within включительно
+func (s *subscription) UpcomingCharges(ctx context.Context, req *models.UpcomingChargesRequest) (_ *models.UpcomingChargesResponse, err error) {
+	ctx, span := tracer.Start(ctx, "subscription.UpcomingCharges")
+	defer tracing.End(span, &err)
+
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+	days, _ := dates.ParseWindow(req.Within)
+	span.SetAttributes(attribute.Int("upcoming.window_days", days))
+
+	from := today().Time
+	to := from.AddDate(0, 0, days)
+
+	subscriptions, err := s.repo.GetActiveSubscriptions(ctx, req.UserID, nil, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.UpcomingChargesResponse{
+		Charges:   []models.UpcomingCharge{},
+		StartDate: dates.NewDate(from),
+		EndDate:   dates.NewDate(to),
+		UserID:    req.UserID,
+	}
+	for _, sub := range subscriptions {
+		date, ok := nextChargeDate(sub, from)
+		if !ok || date.After(to) {
+			continue
+		}
+		response.Charges = append(response.Charges, models.UpcomingCharge{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			UserID:         sub.UserID,
+			ChargeDate:     dates.NewDate(date),
+			Amount:         chargeAmount(sub, sub.Price),
+		})
+		response.TotalAmount += chargeAmount(sub, sub.Price)
+	}
+
+	sort.SliceStable(response.Charges, func(i, j int) bool {
+		a, b := response.Charges[i], response.Charges[j]
+		if !a.ChargeDate.Equal(b.ChargeDate.Time) {
+			return a.ChargeDate.Before(b.ChargeDate.Time)
+		}
+		return a.SubscriptionID < b.SubscriptionID
+	})
+
+	span.SetAttributes(attribute.Int("upcoming.charges", len(response.Charges)))
+	return response, nil
+}
+
+// nextChargeDate возвращает первую дату списания не раньше from. Подписка
+// оплачивается раз в billing_period в день start_date (в коротких месяцах —
+// в последний день). Списания после end_date нет, если подписка не
+// продлевается автоматически.
+func nextChargeDate(sub models.Subscription, from time.Time) (time.Time, bool) {
+	step := sub.BillingPeriod.Months()
+	date := dates.AddMonths(sub.StartDate.Time, firstCharge(sub.StartDate.Time, from, step)*step)
+	if !charged(sub, date) {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// chargeDates возвращает все даты списаний подписки в [from, to] по тем же
+// правилам, что и nextChargeDate
+func chargeDates(sub models.Subscription, from, to time.Time) []time.Time {
+	start := sub.StartDate.Time
+	step := sub.BillingPeriod.Months()
+
+	var result []time.Time
+	for n := firstCharge(start, from, step); ; n++ {
+		date := dates.AddMonths(start, n*step)
+		if date.After(to) || !charged(sub, date) {
+			break
+		}
+		result = append(result, date)
+	}
+	return result
+}
+
+// chargeAmount возвращает сумму одного списания: месячная цена за все
+// месяцы периода
+func chargeAmount(sub models.Subscription, price int) int {
+	return price * sub.BillingPeriod.Months()
+}
+
+// firstCharge возвращает номер первого списания не раньше from при
+// списаниях каждые step месяцев от start
+func firstCharge(start, from time.Time, step int) int {
+	if !start.Before(from) {
+		return 0
+	}
+
+	months := (from.Year()-start.Year())*12 + int(from.Month()-start.Month())
+	n := (months + step - 1) / step
+	if dates.AddMonths(start, n*step).Before(from) {
+		n++
+	}
+	return n
+}
+
+// charged сообщает, будет ли списание в date: после end_date списаний нет,
+// если подписка не продлевается автоматически
+func charged(sub models.Subscription, date time.Time) bool {
+	renews := sub.AutoRenew && !sub.CancelAtPeriodEnd
+	return sub.EndDate == nil || !date.After(sub.EndDate.Time) || renews
+}
@@ -0,0 +1,132 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+func TestChargeDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      models.Subscription
+		from, to string
+		want     []string
+	}{
+		{
+			name: "monthly",
+			sub:  models.Subscription{StartDate: date("2024-01-15")},
+			from: "2024-02-01", to: "2024-04-30",
+			want: []string{"2024-02-15", "2024-03-15", "2024-04-15"},
+		},
+		{
+			name: "empty billing period is monthly",
+			sub:  models.Subscription{StartDate: date("2024-01-15"), BillingPeriod: ""},
+			from: "2024-01-15", to: "2024-02-15",
+			want: []string{"2024-01-15", "2024-02-15"},
+		},
+		{
+			name: "monthly from 31st",
+			sub:  models.Subscription{StartDate: date("2024-01-31"), BillingPeriod: models.BillingMonthly},
+			from: "2024-01-01", to: "2024-04-30",
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"},
+		},
+		{
+			name: "monthly from 30th keeps the day",
+			sub:  models.Subscription{StartDate: date("2023-01-30"), BillingPeriod: models.BillingMonthly},
+			from: "2023-02-01", to: "2023-03-31",
+			want: []string{"2023-02-28", "2023-03-30"},
+		},
+		{
+			name: "quarterly",
+			sub:  models.Subscription{StartDate: date("2024-01-31"), BillingPeriod: models.BillingQuarterly},
+			from: "2024-01-01", to: "2024-12-31",
+			want: []string{"2024-01-31", "2024-04-30", "2024-07-31", "2024-10-31"},
+		},
+		{
+			name: "quarterly skips months between charges",
+			sub:  models.Subscription{StartDate: date("2024-01-10"), BillingPeriod: models.BillingQuarterly},
+			from: "2024-02-01", to: "2024-08-31",
+			want: []string{"2024-04-10", "2024-07-10"},
+		},
+		{
+			name: "quarterly charge on from",
+			sub:  models.Subscription{StartDate: date("2024-01-10"), BillingPeriod: models.BillingQuarterly},
+			from: "2024-04-10", to: "2024-04-10",
+			want: []string{"2024-04-10"},
+		},
+		{
+			name: "yearly from february 29",
+			sub:  models.Subscription{StartDate: date("2024-02-29"), BillingPeriod: models.BillingYearly},
+			from: "2024-03-01", to: "2028-12-31",
+			want: []string{"2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+		},
+		{
+			name: "none before start",
+			sub:  models.Subscription{StartDate: date("2024-06-01"), BillingPeriod: models.BillingYearly},
+			from: "2024-01-01", to: "2024-05-31",
+			want: nil,
+		},
+		{
+			name: "stops at end date",
+			sub:  models.Subscription{StartDate: date("2024-01-15"), EndDate: datePtr("2024-07-14"), BillingPeriod: models.BillingQuarterly},
+			from: "2024-01-01", to: "2024-12-31",
+			want: []string{"2024-01-15", "2024-04-15"},
+		},
+		{
+			name: "auto renew continues after end date",
+			sub: models.Subscription{StartDate: date("2024-01-15"), EndDate: datePtr("2024-01-31"),
+				AutoRenew: true, BillingPeriod: models.BillingQuarterly},
+			from: "2024-02-01", to: "2024-12-31",
+			want: []string{"2024-04-15", "2024-07-15", "2024-10-15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chargeDates(tt.sub, day(tt.from), day(tt.to))
+			if len(got) != len(tt.want) {
+				t.Fatalf("chargeDates() = %v, want %v", formatDays(got), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(day(tt.want[i])) {
+					t.Fatalf("chargeDates() = %v, want %v", formatDays(got), tt.want)
+				}
+			}
+
+			next, ok := nextChargeDate(tt.sub, day(tt.from))
+			if len(tt.want) > 0 && (!ok || !next.Equal(day(tt.want[0]))) {
+				t.Errorf("nextChargeDate() = %s, %v; want %s", next.Format(time.DateOnly), ok, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestChargeAmount(t *testing.T) {
+	tests := []struct {
+		period models.BillingPeriod
+		want   int
+	}{
+		{"", 499},
+		{models.BillingMonthly, 499},
+		{models.BillingQuarterly, 1497},
+		{models.BillingYearly, 5988},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			if got := chargeAmount(models.Subscription{BillingPeriod: tt.period}, 499); got != tt.want {
+				t.Errorf("chargeAmount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func formatDays(days []time.Time) []string {
+	result := make([]string, len(days))
+	for i, d := range days {
+		result[i] = d.Format(time.DateOnly)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logHandler дополняет записи slog идентификаторами трассировки из контекста
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler оборачивает slog.Handler, добавляя trace_id и span_id
+// в записи, созданные через методы логгера с контекстом (InfoContext и т.п.)
+func NewLogHandler(next slog.Handler) slog.Handler {
+	return &logHandler{Handler: next}
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// CreateBudget создает бюджет
+func (h *handler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.CreateBudgetRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	budget, err := h.budgets.CreateBudget(ctx, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create budget", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "budget created", "budget_id", budget.ID, "user_id", budget.UserID)
+	Response(h.logger, w, budget, http.StatusCreated)
+}
+
+// GetBudget получает бюджет по ID
+func (h *handler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	budget, err := h.budgets.GetBudgetByID(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get budget", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	Response(h.logger, w, budget, http.StatusOK)
+}
+
+// GetBudgets получает список бюджетов с фильтрацией
+func (h *handler) GetBudgets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filters := &models.BudgetFilters{
+		Limit:  10, // значение по умолчанию
+		Offset: 0,  // значение по умолчанию
+	}
+
+	query := r.URL.Query()
+
+	if userID := query.Get("user_id"); userID != "" {
+		filters.UserID = &userID
+	}
+
+	if hardStr := query.Get("hard"); hardStr != "" {
+		if hard, err := strconv.ParseBool(hardStr); err == nil {
+			filters.Hard = &hard
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filters.Offset = offset
+		}
+	}
+
+	response, err := h.budgets.GetBudgets(ctx, filters)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get budgets", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// UpdateBudget обновляет лимит, пороги или жесткость бюджета
+func (h *handler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	var req models.UpdateBudgetRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	// Проверяем, что хотя бы одно поле для обновления указано
+	if req.MonthlyLimit == nil && req.Thresholds == nil && req.Hard == nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrNoFieldsToUpdate)
+		return
+	}
+
+	budget, err := h.budgets.UpdateBudget(ctx, id, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update budget", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "budget updated", "budget_id", id)
+	Response(h.logger, w, budget, http.StatusOK)
+}
+
+// DeleteBudget удаляет бюджет вместе с историей оповещений
+func (h *handler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	if err := h.budgets.DeleteBudget(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete budget", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "budget deleted", "budget_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EvaluateBudget сравнивает расходы текущего месяца с лимитом бюджета без
+// отправки оповещений
+func (h *handler) EvaluateBudget(w http.ResponseWriter, r *http.Request) {
+	h.evaluateBudget(w, r, false)
+}
+
+// NotifyBudget проверяет бюджет и отправляет оповещения по достигнутым порогам,
+// которые еще не срабатывали в этом месяце
+func (h *handler) NotifyBudget(w http.ResponseWriter, r *http.Request) {
+	h.evaluateBudget(w, r, true)
+}
+
+func (h *handler) evaluateBudget(w http.ResponseWriter, r *http.Request, notify bool) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	evaluation, err := h.budgets.EvaluateBudget(ctx, id, notify)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to evaluate budget", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	evaluation.SetDateLayout(layout)
+	Response(h.logger, w, evaluation, http.StatusOK)
+}
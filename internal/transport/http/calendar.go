@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/IceMAN2377/market/internal/ical"
+)
+
+// IssueCalendarToken выпускает новый токен календарной ленты пользователя
+func (h *handler) IssueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := r.PathValue("user_id")
+
+	token, err := h.service.IssueCalendarToken(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to issue calendar token", "error", err, "user_id", userID)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	token.FeedURL = "/api/v1/users/" + url.PathEscape(userID) + "/renewals.ics?token=" + url.QueryEscape(token.Token)
+
+	h.logger.InfoContext(ctx, "calendar token issued", "user_id", userID)
+	Response(h.logger, w, token, http.StatusCreated)
+}
+
+// RenewalCalendar отдает календарь продлений в формате iCalendar.
+// Токен передается в параметре token, чтобы ссылку можно было добавить
+// в календарное приложение.
+func (h *handler) RenewalCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := r.PathValue("user_id")
+
+	calendar, err := h.service.RenewalCalendar(ctx, userID, r.URL.Query().Get("token"))
+	if err != nil {
+		h.logger.InfoContext(ctx, "calendar feed rejected", "error", err, "user_id", userID)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	if _, err := calendar.WriteTo(w); err != nil {
+		h.logger.ErrorContext(ctx, "failed on writing calendar: "+err.Error())
+	}
+}
@@ -1,74 +1,189 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/IceMAN2377/market/internal/dates"
 	errs "github.com/IceMAN2377/market/internal/errors"
 	"github.com/IceMAN2377/market/internal/models"
 	"github.com/IceMAN2377/market/internal/service"
+	"github.com/IceMAN2377/market/internal/tracing"
+	"github.com/IceMAN2377/market/internal/validation"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 )
 
-func newHandler(service service.Service, logger *slog.Logger) *handler {
+func newHandler(service service.Service, budgets service.BudgetService, catalog service.CatalogService, logger *slog.Logger) *handler {
 	return &handler{
 		service: service,
+		budgets: budgets,
+		catalog: catalog,
 		logger:  logger,
 	}
 }
 
 type handler struct {
 	service service.Service
+	budgets service.BudgetService
+	catalog service.CatalogService
 	logger  *slog.Logger
 }
 
+var tracer = tracing.Tracer("github.com/IceMAN2377/market/internal/transport/http")
+
+// decodeJSON декодирует тело запроса в отдельном спане
+func decodeJSON(ctx context.Context, r *http.Request, dst any) error {
+	_, span := tracer.Start(ctx, "decode request body")
+	defer span.End()
+
+	err := json.NewDecoder(r.Body).Decode(dst)
+	tracing.RecordError(span, err)
+	return err
+}
+
+// respondDecodeError отвечает на ошибку декодирования тела запроса
+func (h *handler) respondDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	h.logger.ErrorContext(r.Context(), "failed to decode request body", "error", err)
+
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		err = fmt.Errorf("%w: %v", errs.ErrInvalidJSON, err)
+	}
+
+	ResponseWithProblem(h.logger, w, r, err)
+}
+
+// dateLayout читает из параметра date_format формат дат в ответе (по умолчанию ISO)
+func dateLayout(r *http.Request) (dates.Layout, error) {
+	layout, err := dates.ParseLayout(r.URL.Query().Get("date_format"))
+	if err != nil {
+		allowed := make([]string, len(dates.Layouts))
+		for i, l := range dates.Layouts {
+			allowed[i] = string(l)
+		}
+		param := strings.Join(allowed, " ")
+
+		return "", &validation.Errors{Fields: []validation.FieldError{{
+			In:      "query",
+			Field:   "date_format",
+			Code:    "oneof",
+			Message: "must be one of: " + param,
+			Key:     validation.MessageKey("oneof"),
+			Params:  map[string]string{"param": param},
+		}}}
+	}
+	return layout, nil
+}
+
+// queryInt читает целочисленный параметр запроса; пустой параметр дает nil,
+// нечисловой добавляет ошибку поля в verrs
+func queryInt(verrs *validation.Errors, query url.Values, name string) *int {
+	value := query.Get(name)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		verrs.Add(name, validation.CodeInteger, "must be an integer")
+		return nil
+	}
+	return &n
+}
+
+// maxPageLimit наибольший размер страницы списков
+const maxPageLimit = 100
+
+// queryPage читает параметры пагинации limit (от 1 до maxPageLimit) и offset
+// (не меньше 0); без параметра остается значение по умолчанию, значение вне
+// диапазона добавляет ошибку поля в verrs
+func queryPage(verrs *validation.Errors, query url.Values, limit, offset *int) {
+	if n := queryInt(verrs, query, "limit"); n != nil {
+		switch {
+		case *n < 1:
+			verrs.AddParam("limit", "min", "must be at least 1", "1")
+		case *n > maxPageLimit:
+			verrs.AddParam("limit", "max", fmt.Sprintf("must be at most %d", maxPageLimit), strconv.Itoa(maxPageLimit))
+		default:
+			*limit = *n
+		}
+	}
+
+	if n := queryInt(verrs, query, "offset"); n != nil {
+		if *n < 0 {
+			verrs.AddParam("offset", "min", "must be at least 0", "0")
+		} else {
+			*offset = *n
+		}
+	}
+}
+
+// queryBool читает логический параметр запроса так же, как queryInt
+func queryBool(verrs *validation.Errors, query url.Values, name string) *bool {
+	value := query.Get(name)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		verrs.Add(name, validation.CodeBoolean, "must be true or false")
+		return nil
+	}
+	return &b
+}
+
+// allowOverlap читает параметр allow_overlap: разрешить пересечение периода
+// с другими подписками пользователя на тот же сервис. Некорректное значение
+// добавляет ошибку поля в verrs.
+func allowOverlap(verrs *validation.Errors, r *http.Request) bool {
+	allow := queryBool(verrs, r.URL.Query(), "allow_overlap")
+	return allow != nil && *allow
+}
+
+// skipBudgetCheck читает параметр skip_budget_check: сохранить подписку,
+// даже если с ней расходы превысят жесткий бюджет. Некорректное значение
+// добавляет ошибку поля в verrs.
+func skipBudgetCheck(verrs *validation.Errors, r *http.Request) bool {
+	skip := queryBool(verrs, r.URL.Query(), "skip_budget_check")
+	return skip != nil && *skip
+}
+
 // CreateSubscription создает новую подписку
 func (h *handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var req models.CreateSubscriptionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request body", "error", err)
-		ResponseWithError(h.logger, w, "invalid JSON format", http.StatusBadRequest)
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	// Базовая валидация обязательных полей
-	if req.ServiceName == "" {
-		ResponseWithError(h.logger, w, "service_name is required", http.StatusBadRequest)
-		return
-	}
-	if req.UserID == "" {
-		ResponseWithError(h.logger, w, "user_id is required", http.StatusBadRequest)
-		return
-	}
-	if req.StartDate == "" {
-		ResponseWithError(h.logger, w, "start_date is required", http.StatusBadRequest)
+	var req models.CreateSubscriptionRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
 		return
 	}
-	if req.Price <= 0 {
-		ResponseWithError(h.logger, w, "price must be positive", http.StatusBadRequest)
+	var verrs validation.Errors
+	req.AllowOverlap = allowOverlap(&verrs, r)
+	req.SkipBudgetCheck = skipBudgetCheck(&verrs, r)
+	if err := verrs.Err(); err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
 	subscription, err := h.service.CreateSubscription(ctx, &req)
 	if err != nil {
-		h.logger.Error("failed to create subscription", "error", err)
-
-		if errors.Is(err, errs.ErrInvalidUUID) ||
-			errors.Is(err, errs.ErrInvalidDateFormat) ||
-			errors.Is(err, errs.ErrInvalidDateRange) ||
-			errors.Is(err, errs.ErrInvalidPrice) {
-			ResponseWithError(h.logger, w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+		h.logger.ErrorContext(ctx, "failed to create subscription", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	h.logger.Info("subscription created", "subscription_id", subscription.ID, "user_id", subscription.UserID)
+	h.logger.InfoContext(ctx, "subscription created", "subscription_id", subscription.ID, "user_id", subscription.UserID)
+	subscription.SetDateLayout(layout)
 	Response(h.logger, w, subscription, http.StatusCreated)
 }
 
@@ -76,26 +191,27 @@ func (h *handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 func (h *handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		ResponseWithError(h.logger, w, "invalid subscription ID", http.StatusBadRequest)
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
 		return
 	}
 
 	subscription, err := h.service.GetSubscriptionByID(ctx, id)
 	if err != nil {
-		h.logger.Error("failed to get subscription", "error", err, "id", id)
-
-		if errors.Is(err, errs.ErrNotFound) {
-			ResponseWithError(h.logger, w, "subscription not found", http.StatusNotFound)
-			return
-		}
-
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+		h.logger.ErrorContext(ctx, "failed to get subscription", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
+	subscription.SetDateLayout(layout)
 	Response(h.logger, w, subscription, http.StatusOK)
 }
 
@@ -103,6 +219,12 @@ func (h *handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
 func (h *handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
 	filters := &models.SubscriptionFilters{
 		Limit:  10, // значение по умолчанию
 		Offset: 0,  // значение по умолчанию
@@ -111,39 +233,61 @@ func (h *handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
 	// Парсинг query параметров
 	query := r.URL.Query()
 
-	if userID := query.Get("user_id"); userID != "" {
-		filters.UserID = &userID
+	// Несколько пользователей и сервисов передаются повторением параметра:
+	// user_id=...&user_id=...
+	filters.UserIDs = query["user_id"]
+	filters.ServiceNames = query["service_name"]
+
+	// Нечисловые значения не отбрасываются молча, а возвращаются ошибками полей
+	var verrs validation.Errors
+	filters.ServiceID = queryInt(&verrs, query, "service_id")
+
+	if status := query.Get("status"); status != "" {
+		s := models.Status(status)
+		filters.Status = &s
 	}
 
-	if serviceName := query.Get("service_name"); serviceName != "" {
-		filters.ServiceName = &serviceName
+	if category := query.Get("category"); category != "" {
+		filters.Category = &category
 	}
 
-	if limitStr := query.Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			filters.Limit = limit
+	// Метки передаются повторением параметра: tag=work&tag=shared
+	filters.Tags = query["tag"]
+	filters.TagMatch = query.Get("tag_match")
+
+	filters.PriceMin = queryInt(&verrs, query, "price_min")
+	filters.PriceMax = queryInt(&verrs, query, "price_max")
+	filters.HasEndDate = queryBool(&verrs, query, "has_end_date")
+
+	for name, filter := range map[string]**string{
+		"active_at":      &filters.ActiveAt,
+		"started_after":  &filters.StartedAfter,
+		"started_before": &filters.StartedBefore,
+		"ends_before":    &filters.EndsBefore,
+		"created_since":  &filters.CreatedSince,
+	} {
+		if value := query.Get(name); value != "" {
+			*filter = &value
 		}
 	}
 
-	if offsetStr := query.Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
-			filters.Offset = offset
-		}
+	queryPage(&verrs, query, &filters.Limit, &filters.Offset)
+
+	if err := verrs.Err(); err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
 	}
 
 	response, err := h.service.GetSubscriptions(ctx, filters)
 	if err != nil {
-		h.logger.Error("failed to get subscriptions", "error", err)
-
-		if errors.Is(err, errs.ErrInvalidUUID) {
-			ResponseWithError(h.logger, w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+		h.logger.ErrorContext(ctx, "failed to get subscriptions", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
+	for i := range response.Subscriptions {
+		response.Subscriptions[i].SetDateLayout(layout)
+	}
 	Response(h.logger, w, response, http.StatusOK)
 }
 
@@ -151,54 +295,48 @@ func (h *handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
 func (h *handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		ResponseWithError(h.logger, w, "invalid subscription ID", http.StatusBadRequest)
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
 		return
 	}
 
 	var req models.UpdateSubscriptionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request body", "error", err)
-		ResponseWithError(h.logger, w, "invalid JSON format", http.StatusBadRequest)
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
 		return
 	}
-
-	// Проверяем, что хотя бы одно поле для обновления указано
-	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil {
-		ResponseWithError(h.logger, w, "at least one field must be provided for update", http.StatusBadRequest)
+	var verrs validation.Errors
+	req.AllowOverlap = allowOverlap(&verrs, r)
+	req.SkipBudgetCheck = skipBudgetCheck(&verrs, r)
+	if err := verrs.Err(); err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	// Валидация цены, если она указана
-	if req.Price != nil && *req.Price <= 0 {
-		ResponseWithError(h.logger, w, "price must be positive", http.StatusBadRequest)
+	// Проверяем, что хотя бы одно поле для обновления указано
+	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil && req.AutoRenew == nil &&
+		req.BillingPeriod == nil && req.Category == nil && req.Tags == nil && req.Notes == nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrNoFieldsToUpdate)
 		return
 	}
 
 	subscription, err := h.service.UpdateSubscription(ctx, id, &req)
 	if err != nil {
-		h.logger.Error("failed to update subscription", "error", err, "id", id)
-
-		if errors.Is(err, errs.ErrNotFound) {
-			ResponseWithError(h.logger, w, "subscription not found", http.StatusNotFound)
-			return
-		}
-
-		if errors.Is(err, errs.ErrInvalidDateFormat) ||
-			errors.Is(err, errs.ErrInvalidDateRange) ||
-			errors.Is(err, errs.ErrInvalidPrice) ||
-			errors.Is(err, errs.ErrInvalidData) {
-			ResponseWithError(h.logger, w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+		h.logger.ErrorContext(ctx, "failed to update subscription", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	h.logger.Info("subscription updated", "subscription_id", id)
+	h.logger.InfoContext(ctx, "subscription updated", "subscription_id", id)
+	subscription.SetDateLayout(layout)
 	Response(h.logger, w, subscription, http.StatusOK)
 }
 
@@ -209,67 +347,195 @@ func (h *handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		ResponseWithError(h.logger, w, "invalid subscription ID", http.StatusBadRequest)
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
 		return
 	}
 
 	err = h.service.DeleteSubscription(ctx, id)
 	if err != nil {
-		h.logger.Error("failed to delete subscription", "error", err, "id", id)
+		h.logger.ErrorContext(ctx, "failed to delete subscription", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
 
-		if errors.Is(err, errs.ErrNotFound) {
-			ResponseWithError(h.logger, w, "subscription not found", http.StatusNotFound)
-			return
-		}
+	h.logger.InfoContext(ctx, "subscription deleted", "subscription_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
 
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+// GetOverlaps возвращает пары пересекающихся подписок пользователей на один сервис
+func (h *handler) GetOverlaps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	h.logger.Info("subscription deleted", "subscription_id", id)
-	w.WriteHeader(http.StatusNoContent)
+	filters := &models.OverlapFilters{
+		Limit:  10, // значение по умолчанию
+		Offset: 0,  // значение по умолчанию
+	}
+
+	query := r.URL.Query()
+
+	if userID := query.Get("user_id"); userID != "" {
+		filters.UserID = &userID
+	}
+
+	var verrs validation.Errors
+	queryPage(&verrs, query, &filters.Limit, &filters.Offset)
+	if err := verrs.Err(); err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	response, err := h.service.GetOverlaps(ctx, filters)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get overlapping subscriptions", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	for i := range response.Overlaps {
+		response.Overlaps[i].SetDateLayout(layout)
+	}
+	Response(h.logger, w, response, http.StatusOK)
 }
 
-// CalculateCost рассчитывает стоимость подписок за период
-func (h *handler) CalculateCost(w http.ResponseWriter, r *http.Request) {
+// SearchSubscriptions ищет подписки по названию сервиса, заметкам и меткам
+func (h *handler) SearchSubscriptions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var req models.CostCalculationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("failed to decode request body", "error", err)
-		ResponseWithError(h.logger, w, "invalid JSON format", http.StatusBadRequest)
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	// Валидация обязательных полей
-	if req.StartDate == "" {
-		ResponseWithError(h.logger, w, "start_date is required", http.StatusBadRequest)
+	query := r.URL.Query()
+
+	req := &models.SearchRequest{
+		Query:  query.Get("q"),
+		Limit:  10, // значение по умолчанию
+		Offset: 0,  // значение по умолчанию
+	}
+
+	if userID := query.Get("user_id"); userID != "" {
+		req.UserID = &userID
+	}
+
+	var verrs validation.Errors
+	queryPage(&verrs, query, &req.Limit, &req.Offset)
+	if err := verrs.Err(); err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
-	if req.EndDate == "" {
-		ResponseWithError(h.logger, w, "end_date is required", http.StatusBadRequest)
+
+	response, err := h.service.Search(ctx, req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to search subscriptions", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	response, err := h.service.CalculateCost(ctx, &req)
+	for i := range response.Results {
+		response.Results[i].SetDateLayout(layout)
+	}
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// CalculateCost рассчитывает стоимость подписок за период
+func (h *handler) CalculateCost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
 	if err != nil {
-		h.logger.Error("failed to calculate cost", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
 
-		if errors.Is(err, errs.ErrInvalidUUID) ||
-			errors.Is(err, errs.ErrInvalidDateFormat) ||
-			errors.Is(err, errs.ErrInvalidDateRange) {
-			ResponseWithError(h.logger, w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	var req models.CostCalculationRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
 
-		ResponseWithError(h.logger, w, "internal server error", http.StatusInternalServerError)
+	response, err := h.service.CalculateCost(ctx, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to calculate cost", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
 		return
 	}
 
-	h.logger.Info("cost calculated",
+	h.logger.InfoContext(ctx, "cost calculated",
 		"total_cost", response.TotalCost,
 		"start_date", response.StartDate,
 		"end_date", response.EndDate)
 
+	response.SetDateLayout(layout)
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// UpcomingCharges возвращает ближайшие списания по действующим подпискам
+func (h *handler) UpcomingCharges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	req := models.UpcomingChargesRequest{Within: "30d"} // значение по умолчанию
+
+	if within := query.Get("within"); within != "" {
+		req.Within = within
+	}
+
+	if userID := query.Get("user_id"); userID != "" {
+		req.UserID = &userID
+	}
+
+	response, err := h.service.UpcomingCharges(ctx, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get upcoming charges", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	response.SetDateLayout(layout)
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// Forecast прогнозирует помесячные расходы по действующим подпискам
+func (h *handler) Forecast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	var req models.ForecastRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	response, err := h.service.Forecast(ctx, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to forecast spend", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "spend forecast calculated",
+		"months", len(response.Months),
+		"total_amount", response.TotalAmount)
+
+	response.SetDateLayout(layout)
 	Response(h.logger, w, response, http.StatusOK)
 }
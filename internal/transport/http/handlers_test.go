@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Обработчики сами отклоняют некорректные параметры, даже без проверки
+// запросов по спецификации; ответ с ошибками полей проверяется по ней
+func TestInvalidQueryParameters(t *testing.T) {
+	router := newTestRouter(t)
+	validated := newTestHandler(t)
+
+	create := `{"service_name":"Netflix","price":499,"user_id":"` + testUserID + `","start_date":"2024-01"}`
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		fields []string
+	}{
+		{"GET", "/api/v1/subscriptions?service_id=netflix", "", []string{"service_id"}},
+		{"GET", "/api/v1/subscriptions?price_min=1.5", "", []string{"price_min"}},
+		{"GET", "/api/v1/subscriptions?price_max=abc", "", []string{"price_max"}},
+		{"GET", "/api/v1/subscriptions?has_end_date=maybe", "", []string{"has_end_date"}},
+		{"GET", "/api/v1/subscriptions?service_id=x&price_min=y&price_max=z&has_end_date=w", "", []string{"service_id", "price_min", "price_max", "has_end_date"}},
+		{"GET", "/api/v1/subscriptions?user_id=not-a-uuid", "", []string{"user_id"}},
+		{"GET", "/api/v1/subscriptions?user_id=" + testUserID + "&user_id=bad&user_id=worse", "", []string{"user_id"}},
+		{"GET", "/api/v1/subscriptions?limit=abc", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions?limit=-1", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions?limit=0", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions?limit=101", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions?offset=-1", "", []string{"offset"}},
+		{"GET", "/api/v1/subscriptions?limit=x&offset=y", "", []string{"limit", "offset"}},
+		{"GET", "/api/v1/subscriptions/overlaps?limit=abc", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions/overlaps?limit=500&offset=-5", "", []string{"limit", "offset"}},
+		{"GET", "/api/v1/subscriptions/search?q=netflix&limit=-1", "", []string{"limit"}},
+		{"GET", "/api/v1/subscriptions/search?q=netflix&offset=abc", "", []string{"offset"}},
+		{"POST", "/api/v1/subscriptions?allow_overlap=yes", create, []string{"allow_overlap"}},
+		{"PUT", "/api/v1/subscriptions/1?allow_overlap=1x", `{"price":599}`, []string{"allow_overlap"}},
+		{"POST", "/api/v1/subscriptions?skip_budget_check=ture", create, []string{"skip_budget_check"}},
+		{"PUT", "/api/v1/subscriptions/1?skip_budget_check=on", `{"price":599}`, []string{"skip_budget_check"}},
+		{"POST", "/api/v1/subscriptions?allow_overlap=no&skip_budget_check=yes", create, []string{"allow_overlap", "skip_budget_check"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			validated.ServeHTTP(rec, newRequest(tt.method, tt.target, tt.body))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("with openapi validation: status = %d, want %d; body: %s",
+					rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+
+			rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, newRequest(tt.method, tt.target, tt.body))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+
+			var problem struct {
+				Code   string `json:"code"`
+				Fields []struct {
+					Field string `json:"field"`
+					Code  string `json:"code"`
+				} `json:"fields"`
+			}
+			body := rec.Body.String()
+			if err := json.Unmarshal([]byte(body), &problem); err != nil {
+				t.Fatalf("failed to decode problem: %v", err)
+			}
+			if problem.Code != "validation_failed" {
+				t.Errorf("code = %q, want validation_failed", problem.Code)
+			}
+
+			var fields []string
+			for _, f := range problem.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("fields = %v, want %v; body: %s", fields, tt.fields, body)
+			}
+		})
+	}
+}
+
+// newRequest создает запрос с JSON телом, если оно задано
+func newRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func TestValidQueryParameters(t *testing.T) {
+	handler := newTestHandler(t)
+
+	for _, target := range []string{
+		"/api/v1/subscriptions?service_id=1&price_min=100&price_max=500&has_end_date=false&limit=100&offset=0",
+		"/api/v1/subscriptions?user_id=" + testUserID + "&limit=1&offset=20",
+		"/api/v1/subscriptions/overlaps?limit=100&offset=10",
+		"/api/v1/subscriptions/search?q=netflix&limit=1&offset=0",
+	} {
+		t.Run(target, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/IceMAN2377/market/internal/health"
+)
+
+// RegisterHealthEndpoints регистрирует эндпоинты liveness и readiness проб
+func RegisterHealthEndpoints(logger *slog.Logger, router *http.ServeMux, checker *health.Checker) {
+	// Liveness: процесс запущен и обрабатывает запросы
+	router.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		Response(logger, w, map[string]string{"status": health.StatusOK}, http.StatusOK)
+	})
+
+	// Readiness: зависимости доступны, сервис готов принимать трафик
+	router.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Ready(r.Context())
+		if !report.OK() {
+			logger.WarnContext(r.Context(), "readiness check failed", "checks", report.Checks)
+			Response(logger, w, report, http.StatusServiceUnavailable)
+			return
+		}
+
+		Response(logger, w, report, http.StatusOK)
+	})
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// PauseSubscription приостанавливает подписку
+func (h *handler) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "pause", func(ctx context.Context, id int) (*models.Subscription, error) {
+		return h.service.PauseSubscription(ctx, id)
+	})
+}
+
+// ResumeSubscription возобновляет приостановленную подписку
+func (h *handler) ResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "resume", func(ctx context.Context, id int) (*models.Subscription, error) {
+		return h.service.ResumeSubscription(ctx, id)
+	})
+}
+
+// CancelSubscription отменяет подписку сразу или в конце текущего периода.
+// Тело запроса необязательно: без него подписка отменяется сразу.
+func (h *handler) CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	var req models.CancelSubscriptionRequest
+	if err := decodeJSON(r.Context(), r, &req); err != nil && !errors.Is(err, io.EOF) {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	h.changeStatus(w, r, "cancel", func(ctx context.Context, id int) (*models.Subscription, error) {
+		return h.service.CancelSubscription(ctx, id, &req)
+	})
+}
+
+// changeStatus общая часть обработчиков действий над статусом подписки
+func (h *handler) changeStatus(w http.ResponseWriter, r *http.Request, action string, apply func(context.Context, int) (*models.Subscription, error)) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	subscription, err := apply(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to "+action+" subscription", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "subscription status changed",
+		"subscription_id", id,
+		"action", action,
+		"status", subscription.Status)
+
+	subscription.SetDateLayout(layout)
+	Response(h.logger, w, subscription, http.StatusOK)
+}
@@ -0,0 +1,263 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/IceMAN2377/market/internal/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/google/uuid"
+)
+
+func init() {
+	// Формат uuid используется в спецификации для user_id; проверяем так же, как сервис
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(func(value string) error {
+		_, err := uuid.Parse(value)
+		return err
+	}))
+
+	// Календарная лента — текст; проверяется только тип содержимого
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.RegisteredBodyDecoder("text/plain"))
+}
+
+// Код нарушения, когда kin-openapi не сообщает нарушенное ключевое слово схемы
+const codeInvalid = "invalid"
+
+// OpenAPIValidatorOptions параметры проверки по спецификации
+type OpenAPIValidatorOptions struct {
+	// ValidateResponses включает строгий режим: ответы обработчиков тоже
+	// проверяются, а расхождение со спецификацией превращается в 500.
+	// Предназначен для тестов и стендов, чтобы ловить дрейф спецификации.
+	ValidateResponses bool
+}
+
+// OpenAPIValidator проверяет запросы (и опционально ответы) по спецификации OpenAPI
+type OpenAPIValidator struct {
+	router  routers.Router
+	logger  *slog.Logger
+	options OpenAPIValidatorOptions
+}
+
+// NewOpenAPIValidator загружает спецификацию и строит по ней маршрутизатор
+func NewOpenAPIValidator(spec []byte, logger *slog.Logger, options OpenAPIValidatorOptions) (*OpenAPIValidator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	// Серверы из спецификации описывают окружения для документации;
+	// запросы проверяются независимо от хоста, на который они пришли
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &OpenAPIValidator{
+		router:  router,
+		logger:  logger,
+		options: options,
+	}, nil
+}
+
+// Middleware проверяет запросы к операциям из спецификации и отвечает 400
+// со списком всех нарушений. Маршруты вне спецификации (документация,
+// статические ресурсы) пропускаются без проверки.
+func (v *OpenAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				ResponseWithProblem(v.logger, w, r, err)
+				return
+			}
+
+			issues := collectIssues(err)
+			v.logger.InfoContext(r.Context(), "request rejected by openapi validation", "issues", issues)
+			ResponseWithProblem(v.logger, w, r, &validation.Errors{Fields: issues})
+			return
+		}
+
+		if !v.options.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 recorder.status,
+			Header:                 recorder.header,
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options: &openapi3filter.Options{
+				MultiError:            true,
+				IncludeResponseStatus: true,
+			},
+		})
+		if err != nil {
+			issues := collectIssues(err)
+			v.logger.ErrorContext(r.Context(), "response does not match openapi spec",
+				"status", recorder.status, "issues", issues)
+			ResponseWithProblem(v.logger, w, r, &responseMismatchError{fields: issues})
+			return
+		}
+
+		recorder.flushTo(w)
+	})
+}
+
+// collectIssues раскладывает вложенные ошибки kin-openapi в плоский список
+// в том же формате, что и ошибки валидации сервиса
+func collectIssues(err error) []validation.FieldError {
+	var issues []validation.FieldError
+	appendIssues(&issues, err, validation.FieldError{Code: codeInvalid})
+	return issues
+}
+
+func appendIssues(issues *[]validation.FieldError, err error, base validation.FieldError) {
+	// Проверяем конкретный тип, а не цепочку Unwrap: RequestError оборачивает
+	// MultiError, и errors.As потерял бы имя параметра
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, inner := range e {
+			appendIssues(issues, inner, base)
+		}
+	case *openapi3filter.RequestError:
+		issue := base
+		switch {
+		case e.Parameter != nil:
+			issue.In = e.Parameter.In
+			issue.Field = e.Parameter.Name
+		case e.RequestBody != nil:
+			issue.In = "body"
+		}
+		if e.Err == nil {
+			issue.Message = e.Reason
+			*issues = append(*issues, issue)
+			return
+		}
+		appendIssues(issues, e.Err, issue)
+	case *openapi3filter.ResponseError:
+		issue := base
+		issue.In = "response"
+		if e.Err == nil {
+			issue.Message = e.Reason
+			*issues = append(*issues, issue)
+			return
+		}
+		appendIssues(issues, e.Err, issue)
+	case *openapi3.SchemaError:
+		issue := base
+		if pointer := e.JSONPointer(); len(pointer) > 0 && (issue.In == "body" || issue.In == "response") {
+			issue.Field = strings.Join(pointer, ".")
+		}
+		if e.SchemaField != "" {
+			issue.Code = e.SchemaField
+		}
+		issue.Message = e.Reason
+		issue.Key, issue.Params = schemaMessageKey(e)
+		*issues = append(*issues, issue)
+	default:
+		issue := base
+		issue.Message = err.Error()
+		*issues = append(*issues, issue)
+	}
+}
+
+// schemaMessageKey возвращает ключ сообщения i18n и параметры нарушенного
+// ключевого слова схемы. Для остальных ключевых слов перевод не выполняется
+// и клиент получает исходный текст kin-openapi.
+func schemaMessageKey(e *openapi3.SchemaError) (string, map[string]string) {
+	key := "validation.openapi." + e.SchemaField
+	if e.Schema == nil {
+		return key, nil
+	}
+
+	switch e.SchemaField {
+	case "minimum":
+		if e.Schema.Min != nil {
+			return key, map[string]string{"param": fmt.Sprint(*e.Schema.Min)}
+		}
+	case "maximum":
+		if e.Schema.Max != nil {
+			return key, map[string]string{"param": fmt.Sprint(*e.Schema.Max)}
+		}
+	case "minLength":
+		return key, map[string]string{"param": fmt.Sprint(e.Schema.MinLength)}
+	case "maxLength":
+		if e.Schema.MaxLength != nil {
+			return key, map[string]string{"param": fmt.Sprint(*e.Schema.MaxLength)}
+		}
+	case "format":
+		return key, map[string]string{"format": e.Schema.Format}
+	case "type":
+		if e.Schema.Type != nil {
+			return key, map[string]string{"type": strings.Join(e.Schema.Type.Slice(), ", ")}
+		}
+	}
+	return key, nil
+}
+
+// bufferedResponseWriter накапливает ответ для проверки перед отправкой клиенту
+type bufferedResponseWriter struct {
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+func (w *bufferedResponseWriter) flushTo(dst http.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	dst.WriteHeader(w.status)
+	dst.Write(w.body.Bytes())
+}
@@ -0,0 +1,410 @@
+package http_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	market "github.com/IceMAN2377/market"
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/health"
+	"github.com/IceMAN2377/market/internal/i18n"
+	"github.com/IceMAN2377/market/internal/models"
+	"github.com/IceMAN2377/market/internal/notify"
+	"github.com/IceMAN2377/market/internal/repository"
+	"github.com/IceMAN2377/market/internal/service/budget"
+	"github.com/IceMAN2377/market/internal/service/catalog"
+	"github.com/IceMAN2377/market/internal/service/subscription"
+	v1 "github.com/IceMAN2377/market/internal/transport/http"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	testUserID        = "123e4567-e89b-12d3-a456-426614174000"
+	testCalendarToken = "secret"
+)
+
+// Подписка с этим ID приостановлена, остальные действуют
+const pausedSubscriptionID = 2
+
+// TestOpenAPIResponses прогоняет каждую операцию спецификации через настоящий
+// роутер в строгом режиме: ответ, не совпадающий со swagger.yaml, превращается
+// в 500, и тест падает на расхождении статуса.
+func TestOpenAPIResponses(t *testing.T) {
+	future := time.Now().UTC().AddDate(1, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		method string
+		path   string // путь из спецификации, которому соответствует запрос
+		target string
+		body   string
+		status int
+	}{
+		{"GET", "/api/v1/subscriptions", "/api/v1/subscriptions?user_id=" + testUserID + "&price_min=100&active_at=2024-06&tag=work", "", http.StatusOK},
+		{"POST", "/api/v1/subscriptions", "/api/v1/subscriptions", `{"service_name":"Netflix","price":499,"user_id":"` + testUserID + `","start_date":"2024-01","tags":["work"]}`, http.StatusCreated},
+		{"POST", "/api/v1/subscriptions", "/api/v1/subscriptions", `{"service_name":"Netflix","user_id":"` + testUserID + `","start_date":"2024-01"}`, http.StatusBadRequest},
+		{"GET", "/api/v1/subscriptions/overlaps", "/api/v1/subscriptions/overlaps?user_id=" + testUserID, "", http.StatusOK},
+		{"GET", "/api/v1/subscriptions/search", "/api/v1/subscriptions/search?q=netflix", "", http.StatusOK},
+		{"GET", "/api/v1/subscriptions/{id}", "/api/v1/subscriptions/1", "", http.StatusOK},
+		{"GET", "/api/v1/subscriptions/{id}", "/api/v1/subscriptions/404", "", http.StatusNotFound},
+		{"PUT", "/api/v1/subscriptions/{id}", "/api/v1/subscriptions/1", `{"price":599,"category":"streaming"}`, http.StatusOK},
+		{"DELETE", "/api/v1/subscriptions/{id}", "/api/v1/subscriptions/1", "", http.StatusNoContent},
+		{"POST", "/api/v1/subscriptions/{id}/pause", "/api/v1/subscriptions/1/pause", "", http.StatusOK},
+		{"POST", "/api/v1/subscriptions/{id}/resume", "/api/v1/subscriptions/2/resume", "", http.StatusOK},
+		{"POST", "/api/v1/subscriptions/{id}/resume", "/api/v1/subscriptions/1/resume", "", http.StatusConflict},
+		{"POST", "/api/v1/subscriptions/{id}/cancel", "/api/v1/subscriptions/1/cancel", `{"at_period_end":true}`, http.StatusOK},
+		{"POST", "/api/v1/subscriptions/cost-calculation", "/api/v1/subscriptions/cost-calculation", `{"start_date":"2024-01","end_date":"2024-12","group_by":"tag"}`, http.StatusOK},
+		{"GET", "/api/v1/subscriptions/upcoming", "/api/v1/subscriptions/upcoming?within=30d", "", http.StatusOK},
+		{"POST", "/api/v1/subscriptions/forecast", "/api/v1/subscriptions/forecast", `{"months":3}`, http.StatusOK},
+		{"POST", "/api/v1/subscriptions/{id}/price-changes", "/api/v1/subscriptions/1/price-changes", `{"effective_date":"` + future + `","price":699}`, http.StatusCreated},
+		{"GET", "/api/v1/subscriptions/{id}/price-changes", "/api/v1/subscriptions/1/price-changes", "", http.StatusOK},
+		{"DELETE", "/api/v1/subscriptions/{id}/price-changes/{change_id}", "/api/v1/subscriptions/1/price-changes/1", "", http.StatusNoContent},
+		{"POST", "/api/v1/users/{user_id}/calendar-token", "/api/v1/users/" + testUserID + "/calendar-token", "", http.StatusCreated},
+		{"GET", "/api/v1/users/{user_id}/renewals.ics", "/api/v1/users/" + testUserID + "/renewals.ics?token=" + testCalendarToken, "", http.StatusOK},
+		{"GET", "/api/v1/users/{user_id}/renewals.ics", "/api/v1/users/" + testUserID + "/renewals.ics?token=wrong", "", http.StatusForbidden},
+		{"GET", "/api/v1/budgets", "/api/v1/budgets?user_id=" + testUserID, "", http.StatusOK},
+		{"POST", "/api/v1/budgets", "/api/v1/budgets", `{"user_id":"` + testUserID + `","category":"streaming","monthly_limit":1500}`, http.StatusCreated},
+		{"GET", "/api/v1/budgets/{id}", "/api/v1/budgets/1", "", http.StatusOK},
+		{"PUT", "/api/v1/budgets/{id}", "/api/v1/budgets/1", `{"monthly_limit":2000}`, http.StatusOK},
+		{"DELETE", "/api/v1/budgets/{id}", "/api/v1/budgets/1", "", http.StatusNoContent},
+		{"GET", "/api/v1/budgets/{id}/evaluation", "/api/v1/budgets/1/evaluation", "", http.StatusOK},
+		{"POST", "/api/v1/budgets/{id}/evaluation", "/api/v1/budgets/1/evaluation", "", http.StatusOK},
+		{"GET", "/api/v1/services", "/api/v1/services?category=video", "", http.StatusOK},
+		{"POST", "/api/v1/services", "/api/v1/services", `{"name":"Netflix","aliases":["netflix.com"],"default_price":499}`, http.StatusCreated},
+		{"GET", "/api/v1/services/{id}", "/api/v1/services/1", "", http.StatusOK},
+		{"PUT", "/api/v1/services/{id}", "/api/v1/services/1", `{"default_price":599}`, http.StatusOK},
+		{"DELETE", "/api/v1/services/{id}", "/api/v1/services/1", "", http.StatusNoContent},
+		{"GET", "/healthz", "/healthz", "", http.StatusOK},
+		{"GET", "/readyz", "/readyz", "", http.StatusOK},
+	}
+
+	handler := newTestHandler(t)
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		covered[tt.method+" "+tt.path] = true
+
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+
+	// Каждая операция спецификации должна быть проверена хотя бы одним запросом
+	doc, err := openapi3.NewLoader().LoadFromData(market.SwaggerSpec)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !covered[method+" "+path] {
+				t.Errorf("operation %s %s is not covered by the test", method, path)
+			}
+		}
+	}
+}
+
+// newTestHandler собирает роутер с настоящими сервисами поверх фиктивного
+// репозитория и проверкой запросов и ответов по спецификации
+func newTestHandler(t *testing.T) http.Handler {
+	return newTestServer(t, true)
+}
+
+// newTestRouter собирает тот же роутер без проверки по спецификации, как при
+// OPENAPI_VALIDATE_REQUESTS=false: запросы доходят до обработчиков как есть
+func newTestRouter(t *testing.T) http.Handler {
+	return newTestServer(t, false)
+}
+
+func newTestServer(t *testing.T, validate bool) http.Handler {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := &fakeRepository{}
+
+	budgets := budget.NewService(repo, notify.NewLogNotifier(logger), []int{80, 100}, logger)
+	service := subscription.NewService(repo, dates.Bounds{MinYear: 2000, MaxYearsAhead: 10}, budgets)
+
+	docs, err := v1.NewSwaggerDocs(market.SwaggerSpec)
+	if err != nil {
+		t.Fatalf("failed to prepare swagger docs: %v", err)
+	}
+
+	router := http.NewServeMux()
+	v1.RegisterEndpoints(logger, router, service, budgets, catalog.NewService(repo), health.NewChecker(time.Second), docs)
+
+	locales, err := fs.Sub(market.Locales, market.LocalesDir)
+	if err != nil {
+		t.Fatalf("failed to open locales: %v", err)
+	}
+	bundle, err := i18n.NewBundle(locales, "en")
+	if err != nil {
+		t.Fatalf("failed to load locales: %v", err)
+	}
+
+	var next http.Handler = router
+	if validate {
+		validator, err := v1.NewOpenAPIValidator(market.SwaggerSpec, logger, v1.OpenAPIValidatorOptions{ValidateResponses: true})
+		if err != nil {
+			t.Fatalf("failed to create openapi validator: %v", err)
+		}
+		next = validator.Middleware(router)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := i18n.WithLocalizer(r.Context(), bundle.Localizer("en"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// fakeRepository возвращает заполненные фикстуры для любых запросов; подписка
+// с ID 404 не существует
+type fakeRepository struct {
+	repository.Repository
+}
+
+func testSubscription(id int) *models.Subscription {
+	category := "streaming"
+	notes := "family plan"
+	serviceID := 1
+	sub := &models.Subscription{
+		ID:            id,
+		ServiceName:   "Netflix",
+		ServiceID:     &serviceID,
+		Price:         499,
+		UserID:        testUserID,
+		StartDate:     dates.NewDate(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+		CreatedAt:     time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		Category:      &category,
+		Tags:          []string{"family", "work"},
+		Notes:         &notes,
+		Status:        models.StatusActive,
+		BillingPeriod: models.BillingYearly,
+	}
+	if id == pausedSubscriptionID {
+		sub.Status = models.StatusPaused
+		sub.Pauses = []models.Pause{{SubscriptionID: id, StartDate: dates.NewDate(time.Now().UTC().AddDate(0, 0, -3))}}
+	}
+	return sub
+}
+
+func testBudget(id int) *models.Budget {
+	return &models.Budget{
+		ID:           id,
+		UserID:       testUserID,
+		MonthlyLimit: 100000,
+		Thresholds:   models.Thresholds{80, 100},
+		Hard:         true,
+		CreatedAt:    time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func testService(id int) *models.Service {
+	price := 499
+	category := "video"
+	return &models.Service{
+		ID:           id,
+		Name:         "Netflix",
+		Aliases:      []string{"netflix.com"},
+		DefaultPrice: &price,
+		Category:     &category,
+		CreatedAt:    time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func testPriceChange(subscriptionID int) models.PriceChange {
+	return models.PriceChange{
+		ID:             1,
+		SubscriptionID: subscriptionID,
+		EffectiveDate:  dates.NewDate(time.Now().UTC().AddDate(0, 1, 0)),
+		Price:          699,
+		CreatedAt:      time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func (r *fakeRepository) CreateSubscription(ctx context.Context, sub *models.Subscription) (*models.Subscription, error) {
+	created := *sub
+	created.ID = 1
+	created.Status = models.StatusActive
+	created.CreatedAt = time.Now().UTC()
+	created.UpdatedAt = created.CreatedAt
+	return &created, nil
+}
+
+func (r *fakeRepository) GetSubscriptionByID(ctx context.Context, id int) (*models.Subscription, error) {
+	if id == 404 {
+		return nil, errs.ErrNotFound
+	}
+	return testSubscription(id), nil
+}
+
+func (r *fakeRepository) GetSubscriptions(ctx context.Context, filters *models.SubscriptionFilters) ([]models.Subscription, error) {
+	return []models.Subscription{*testSubscription(1), *testSubscription(pausedSubscriptionID)}, nil
+}
+
+func (r *fakeRepository) UpdateSubscription(ctx context.Context, id int, updates *models.UpdateSubscriptionRequest) (*models.Subscription, error) {
+	sub := testSubscription(id)
+	if updates.Price != nil {
+		sub.Price = *updates.Price
+	}
+	return sub, nil
+}
+
+func (r *fakeRepository) DeleteSubscription(ctx context.Context, id int) error {
+	return nil
+}
+
+func (r *fakeRepository) ChangeStatus(ctx context.Context, id int, change *models.StatusChange) (*models.Subscription, error) {
+	sub := testSubscription(id)
+	sub.Status = change.To
+	sub.CancelAtPeriodEnd = change.CancelAtPeriodEnd
+	sub.CancelledAt = change.CancelledAt
+	if change.EndDate != nil {
+		sub.EndDate = change.EndDate
+	}
+	return sub, nil
+}
+
+func (r *fakeRepository) GetSubscriptionsForPeriod(ctx context.Context, req *models.CostCalculationRequest, from, to time.Time) ([]models.Subscription, error) {
+	return []models.Subscription{*testSubscription(1)}, nil
+}
+
+func (r *fakeRepository) GetActiveSubscriptions(ctx context.Context, userID, serviceName *string, from, to time.Time) ([]models.Subscription, error) {
+	return []models.Subscription{*testSubscription(1)}, nil
+}
+
+func (r *fakeRepository) SearchSubscriptions(ctx context.Context, req *models.SearchRequest) ([]models.SearchResult, error) {
+	return []models.SearchResult{{
+		Subscription: *testSubscription(1),
+		Rank:         0.8,
+		Highlights:   models.SearchHighlights{ServiceName: models.HighlightStart + "Netflix" + models.HighlightStop},
+	}}, nil
+}
+
+func (r *fakeRepository) FindOverlaps(ctx context.Context, sub *models.Subscription) ([]int, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetOverlaps(ctx context.Context, filters *models.OverlapFilters) ([]models.SubscriptionOverlap, error) {
+	return []models.SubscriptionOverlap{{
+		UserID:         testUserID,
+		ServiceName:    "Netflix",
+		SubscriptionID: 1,
+		OverlappingID:  3,
+		StartDate:      dates.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+	}}, nil
+}
+
+func (r *fakeRepository) CreatePriceChange(ctx context.Context, change *models.PriceChange) (*models.PriceChange, error) {
+	created := *change
+	created.ID = 1
+	created.CreatedAt = time.Now().UTC()
+	return &created, nil
+}
+
+func (r *fakeRepository) GetPriceChanges(ctx context.Context, subscriptionID int) ([]models.PriceChange, error) {
+	return []models.PriceChange{testPriceChange(subscriptionID)}, nil
+}
+
+func (r *fakeRepository) GetPendingPriceChanges(ctx context.Context, subscriptionIDs []int) ([]models.PriceChange, error) {
+	return []models.PriceChange{testPriceChange(1)}, nil
+}
+
+func (r *fakeRepository) DeletePriceChange(ctx context.Context, subscriptionID, id int) error {
+	return nil
+}
+
+func (r *fakeRepository) CreateService(ctx context.Context, service *models.Service) (*models.Service, error) {
+	created := testService(1)
+	created.Name = service.Name
+	created.Aliases = service.Aliases
+	created.DefaultPrice = service.DefaultPrice
+	created.Category = service.Category
+	return created, nil
+}
+
+func (r *fakeRepository) GetServiceByID(ctx context.Context, id int) (*models.Service, error) {
+	return testService(id), nil
+}
+
+func (r *fakeRepository) GetServices(ctx context.Context, filters *models.ServiceFilters) ([]models.Service, error) {
+	return []models.Service{*testService(1)}, nil
+}
+
+func (r *fakeRepository) UpdateService(ctx context.Context, id int, updates *models.UpdateServiceRequest) (*models.Service, error) {
+	return testService(id), nil
+}
+
+func (r *fakeRepository) DeleteService(ctx context.Context, id int) error {
+	return nil
+}
+
+func (r *fakeRepository) MatchService(ctx context.Context, name string) (*models.Service, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) CreateBudget(ctx context.Context, b *models.Budget) (*models.Budget, error) {
+	created := testBudget(1)
+	created.ServiceName = b.ServiceName
+	created.Category = b.Category
+	created.MonthlyLimit = b.MonthlyLimit
+	created.Thresholds = b.Thresholds
+	created.Hard = b.Hard
+	return created, nil
+}
+
+func (r *fakeRepository) GetBudgetByID(ctx context.Context, id int) (*models.Budget, error) {
+	return testBudget(id), nil
+}
+
+func (r *fakeRepository) GetBudgets(ctx context.Context, filters *models.BudgetFilters) ([]models.Budget, error) {
+	if filters.Offset > 0 {
+		return []models.Budget{}, nil
+	}
+	return []models.Budget{*testBudget(1)}, nil
+}
+
+func (r *fakeRepository) UpdateBudget(ctx context.Context, id int, updates *models.UpdateBudgetRequest) (*models.Budget, error) {
+	return testBudget(id), nil
+}
+
+func (r *fakeRepository) DeleteBudget(ctx context.Context, id int) error {
+	return nil
+}
+
+func (r *fakeRepository) RecordBudgetAlert(ctx context.Context, alert *models.BudgetAlert) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeRepository) DeleteBudgetAlert(ctx context.Context, alert *models.BudgetAlert) error {
+	return nil
+}
+
+func (r *fakeRepository) SaveCalendarToken(ctx context.Context, userID string, tokenHash []byte) error {
+	return nil
+}
+
+func (r *fakeRepository) GetCalendarTokenHash(ctx context.Context, userID string) ([]byte, error) {
+	sum := sha256.Sum256([]byte(testCalendarToken))
+	return sum[:], nil
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// SchedulePriceChange планирует изменение цены подписки
+func (h *handler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	var req models.SchedulePriceChangeRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	change, err := h.service.SchedulePriceChange(ctx, id, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to schedule price change", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "price change scheduled",
+		"subscription_id", id, "price_change_id", change.ID, "effective_date", change.EffectiveDate)
+	change.SetDateLayout(layout)
+	Response(h.logger, w, change, http.StatusCreated)
+}
+
+// GetPriceChanges возвращает изменения цены подписки
+func (h *handler) GetPriceChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	layout, err := dateLayout(r)
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	response, err := h.service.GetPriceChanges(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get price changes", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	for i := range response.PriceChanges {
+		response.PriceChanges[i].SetDateLayout(layout)
+	}
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// DeletePriceChange отменяет запланированное изменение цены
+func (h *handler) DeletePriceChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	changeID, err := strconv.Atoi(r.PathValue("change_id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	if err := h.service.DeletePriceChange(ctx, id, changeID); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete price change", "error", err, "id", id, "price_change_id", changeID)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "price change deleted", "subscription_id", id, "price_change_id", changeID)
+	w.WriteHeader(http.StatusNoContent)
+}
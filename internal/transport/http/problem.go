@@ -0,0 +1,212 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/i18n"
+	"github.com/IceMAN2377/market/internal/logging"
+	"github.com/IceMAN2377/market/internal/validation"
+)
+
+// Префикс поля type: стабильный идентификатор вида ошибки (RFC 7807)
+const problemTypePrefix = "urn:market:problem:"
+
+// Стабильные машиночитаемые коды ошибок. Клиенты сопоставляют по ним, а не по тексту
+const (
+	CodeValidationFailed         = "validation_failed"
+	CodeResponseValidationFailed = "response_validation_failed"
+	CodeRequestTooLarge          = "request_too_large"
+	CodeInvalidJSON              = "invalid_json"
+	CodeInvalidID                = "invalid_id"
+	CodeInvalidData              = "invalid_data"
+	CodeNoFieldsToUpdate         = "no_fields_to_update"
+	CodeInvalidDateFormat        = "invalid_date_format"
+	CodeInvalidDateRange         = "invalid_date_range"
+	CodeInvalidUUID              = "invalid_uuid"
+	CodeInvalidPrice             = "invalid_price"
+	CodeInvalidPagination        = "invalid_pagination"
+	CodeMissingRequiredField     = "missing_required_field"
+	CodeNotFound                 = "subscription_not_found"
+	CodeAlreadyExists            = "subscription_already_exists"
+	CodeInvalidStatusTransition  = "invalid_status_transition"
+	CodeInvalidCalendarToken     = "invalid_calendar_token"
+	CodePriceChangeNotFound      = "price_change_not_found"
+	CodePriceChangeAlreadyExists = "price_change_already_exists"
+	CodeBudgetNotFound           = "budget_not_found"
+	CodeBudgetAlreadyExists      = "budget_already_exists"
+	CodeBudgetExceeded           = "budget_exceeded"
+	CodeServiceNotFound          = "service_not_found"
+	CodeServiceAlreadyExists     = "service_already_exists"
+	CodeDatabaseUnavailable      = "database_unavailable"
+	CodeTimeout                  = "timeout"
+	CodeInternal                 = "internal_error"
+)
+
+// problemKind описывает, как ошибка отображается в HTTP ответ
+type problemKind struct {
+	Status int
+	Code   string
+	Title  string
+}
+
+// Вид ошибки для всего, что не найдено в реестре. Детали таких ошибок
+// клиенту не раскрываются.
+var internalProblem = problemKind{http.StatusInternalServerError, CodeInternal, "Internal server error"}
+
+// errorRegistry сопоставляет sentinel-ошибки пакета errs с видами ответа.
+// Проверяется по порядку через errors.Is, поэтому более частные ошибки идут раньше.
+var errorRegistry = []struct {
+	err  error
+	kind problemKind
+}{
+	{errs.ErrNotFound, problemKind{http.StatusNotFound, CodeNotFound, "Subscription not found"}},
+	{errs.ErrAlreadyExists, problemKind{http.StatusConflict, CodeAlreadyExists, "Subscription already exists"}},
+	{errs.ErrInvalidStatusTransition, problemKind{http.StatusConflict, CodeInvalidStatusTransition, "Invalid status transition"}},
+	{errs.ErrPriceChangeNotFound, problemKind{http.StatusNotFound, CodePriceChangeNotFound, "Price change not found"}},
+	{errs.ErrPriceChangeAlreadyExists, problemKind{http.StatusConflict, CodePriceChangeAlreadyExists, "Price change already exists"}},
+	{errs.ErrBudgetNotFound, problemKind{http.StatusNotFound, CodeBudgetNotFound, "Budget not found"}},
+	{errs.ErrBudgetAlreadyExists, problemKind{http.StatusConflict, CodeBudgetAlreadyExists, "Budget already exists"}},
+	{errs.ErrBudgetExceeded, problemKind{http.StatusConflict, CodeBudgetExceeded, "Budget exceeded"}},
+	{errs.ErrServiceNotFound, problemKind{http.StatusNotFound, CodeServiceNotFound, "Service not found"}},
+	{errs.ErrServiceAlreadyExists, problemKind{http.StatusConflict, CodeServiceAlreadyExists, "Service already exists"}},
+	{errs.ErrInvalidCalendarToken, problemKind{http.StatusForbidden, CodeInvalidCalendarToken, "Invalid calendar token"}},
+	{errs.ErrValidationFailed, problemKind{http.StatusBadRequest, CodeValidationFailed, "Validation failed"}},
+	{errs.ErrInvalidJSON, problemKind{http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON"}},
+	{errs.ErrInvalidID, problemKind{http.StatusBadRequest, CodeInvalidID, "Invalid subscription ID"}},
+	{errs.ErrNoFieldsToUpdate, problemKind{http.StatusBadRequest, CodeNoFieldsToUpdate, "No fields to update"}},
+	{errs.ErrInvalidDateFormat, problemKind{http.StatusBadRequest, CodeInvalidDateFormat, "Invalid date format"}},
+	{errs.ErrInvalidDateRange, problemKind{http.StatusBadRequest, CodeInvalidDateRange, "Invalid date range"}},
+	{errs.ErrInvalidUUID, problemKind{http.StatusBadRequest, CodeInvalidUUID, "Invalid UUID"}},
+	{errs.ErrInvalidPrice, problemKind{http.StatusBadRequest, CodeInvalidPrice, "Invalid price"}},
+	{errs.ErrInvalidPagination, problemKind{http.StatusBadRequest, CodeInvalidPagination, "Invalid pagination parameters"}},
+	{errs.ErrMissingRequiredField, problemKind{http.StatusBadRequest, CodeMissingRequiredField, "Missing required field"}},
+	{errs.ErrInvalidData, problemKind{http.StatusBadRequest, CodeInvalidData, "Invalid data"}},
+	{errs.ErrDatabaseConnection, problemKind{http.StatusServiceUnavailable, CodeDatabaseUnavailable, "Database unavailable"}},
+	{context.DeadlineExceeded, problemKind{http.StatusGatewayTimeout, CodeTimeout, "Request timed out"}},
+}
+
+// responseMismatchError ответ обработчика не соответствует спецификации OpenAPI
+type responseMismatchError struct {
+	fields []validation.FieldError
+}
+
+func (e *responseMismatchError) Error() string {
+	return "response does not match openapi spec"
+}
+
+// detailParams реализуют ошибки, перевод описания которых содержит параметры
+type detailParams interface {
+	DetailParams() map[string]string
+}
+
+// problem тело ответа application/problem+json (RFC 7807) с расширениями
+type problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      string                  `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Fields    []validation.FieldError `json:"fields,omitempty"`
+
+	// ID подписок, с которыми пересекается период (code = subscription_already_exists)
+	SubscriptionIDs []int `json:"subscription_ids,omitempty"`
+}
+
+// resolveProblem находит вид ошибки: сначала типизированные ошибки, затем реестр
+func resolveProblem(err error) (problemKind, []validation.FieldError) {
+	var verrs *validation.Errors
+	if errors.As(err, &verrs) {
+		return problemKind{http.StatusBadRequest, CodeValidationFailed, "Validation failed"}, verrs.Fields
+	}
+
+	var mismatch *responseMismatchError
+	if errors.As(err, &mismatch) {
+		return problemKind{http.StatusInternalServerError, CodeResponseValidationFailed, "Response validation failed"}, mismatch.fields
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return problemKind{http.StatusRequestEntityTooLarge, CodeRequestTooLarge, "Request body too large"}, nil
+	}
+
+	for _, entry := range errorRegistry {
+		if errors.Is(err, entry.err) {
+			return entry.kind, nil
+		}
+	}
+
+	return internalProblem, nil
+}
+
+// ResponseWithProblem отвечает ошибкой в формате application/problem+json.
+// Статус и код берутся из реестра, заголовок и описания переводятся на язык
+// запроса; текст внутренних ошибок клиенту не отдается.
+func ResponseWithProblem(logger *slog.Logger, w http.ResponseWriter, r *http.Request, err error) {
+	kind, fields := resolveProblem(err)
+	localizer := i18n.FromContext(r.Context())
+
+	resp := problem{
+		Type:      problemTypePrefix + kind.Code,
+		Title:     kind.Title,
+		Status:    kind.Status,
+		Instance:  r.URL.Path,
+		Code:      kind.Code,
+		RequestID: logging.RequestID(r.Context()),
+		Fields:    localizeFields(localizer, fields),
+	}
+	if title, ok := localizer.Message("titles."+kind.Code, nil); ok {
+		resp.Title = title
+	}
+
+	var overlapErr *errs.OverlapError
+	if errors.As(err, &overlapErr) {
+		resp.SubscriptionIDs = overlapErr.SubscriptionIDs
+	}
+
+	// Переведенное описание — фиксированный текст, его можно отдавать и для 5xx
+	params := map[string]string{"count": strconv.Itoa(len(fields))}
+	var withParams detailParams
+	if errors.As(err, &withParams) {
+		params = withParams.DetailParams()
+	}
+	if detail, ok := localizer.Message("errors."+kind.Code, params); ok {
+		resp.Detail = detail
+	} else if len(fields) > 0 {
+		// Подробности — в списке fields, detail лишь кратко их резюмирует
+		resp.Detail = fmt.Sprintf("%d field(s) failed validation", len(fields))
+	} else if kind.Status < http.StatusInternalServerError {
+		resp.Detail = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(kind.Status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error("failed on encoding json error: " + err.Error())
+	}
+}
+
+// localizeFields возвращает копию нарушений с сообщениями на языке запроса.
+// Если перевода нет, остается исходное сообщение на английском.
+func localizeFields(localizer *i18n.Localizer, fields []validation.FieldError) []validation.FieldError {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	localized := make([]validation.FieldError, len(fields))
+	for i, f := range fields {
+		if message, ok := localizer.Message(f.Key, f.Params); ok {
+			f.Message = message
+		}
+		localized[i] = f
+	}
+	return localized
+}
@@ -1,24 +1,62 @@
 package http
 
 import (
+	"github.com/IceMAN2377/market/internal/health"
 	"github.com/IceMAN2377/market/internal/service"
 
 	"log/slog"
 	"net/http"
 )
 
-func RegisterEndpoints(logger *slog.Logger, router *http.ServeMux, service service.Service) {
-	handler := newHandler(service, logger)
+func RegisterEndpoints(logger *slog.Logger, router *http.ServeMux, service service.Service, budgets service.BudgetService, catalog service.CatalogService, checker *health.Checker, docs *SwaggerDocs) {
+	handler := newHandler(service, budgets, catalog, logger)
 
 	// CRUD операции для подписок
 	router.HandleFunc("POST /api/v1/subscriptions", handler.CreateSubscription)
 	router.HandleFunc("GET /api/v1/subscriptions", handler.GetSubscriptions)
+	router.HandleFunc("GET /api/v1/subscriptions/overlaps", handler.GetOverlaps)
+	router.HandleFunc("GET /api/v1/subscriptions/search", handler.SearchSubscriptions)
 	router.HandleFunc("GET /api/v1/subscriptions/{id}", handler.GetSubscription)
 	router.HandleFunc("PUT /api/v1/subscriptions/{id}", handler.UpdateSubscription)
 	router.HandleFunc("DELETE /api/v1/subscriptions/{id}", handler.DeleteSubscription)
 
+	// Жизненный цикл подписки
+	router.HandleFunc("POST /api/v1/subscriptions/{id}/pause", handler.PauseSubscription)
+	router.HandleFunc("POST /api/v1/subscriptions/{id}/resume", handler.ResumeSubscription)
+	router.HandleFunc("POST /api/v1/subscriptions/{id}/cancel", handler.CancelSubscription)
+
 	// Расчет стоимости
 	router.HandleFunc("POST /api/v1/subscriptions/cost-calculation", handler.CalculateCost)
 
-	RegisterSwaggerEndpoints(router)
+	// Предстоящие списания и прогноз расходов
+	router.HandleFunc("GET /api/v1/subscriptions/upcoming", handler.UpcomingCharges)
+	router.HandleFunc("POST /api/v1/subscriptions/forecast", handler.Forecast)
+
+	// Запланированные изменения цены
+	router.HandleFunc("POST /api/v1/subscriptions/{id}/price-changes", handler.SchedulePriceChange)
+	router.HandleFunc("GET /api/v1/subscriptions/{id}/price-changes", handler.GetPriceChanges)
+	router.HandleFunc("DELETE /api/v1/subscriptions/{id}/price-changes/{change_id}", handler.DeletePriceChange)
+
+	// Календарная лента продлений
+	router.HandleFunc("POST /api/v1/users/{user_id}/calendar-token", handler.IssueCalendarToken)
+	router.HandleFunc("GET /api/v1/users/{user_id}/renewals.ics", handler.RenewalCalendar)
+
+	// Бюджеты
+	router.HandleFunc("POST /api/v1/budgets", handler.CreateBudget)
+	router.HandleFunc("GET /api/v1/budgets", handler.GetBudgets)
+	router.HandleFunc("GET /api/v1/budgets/{id}", handler.GetBudget)
+	router.HandleFunc("PUT /api/v1/budgets/{id}", handler.UpdateBudget)
+	router.HandleFunc("DELETE /api/v1/budgets/{id}", handler.DeleteBudget)
+	router.HandleFunc("GET /api/v1/budgets/{id}/evaluation", handler.EvaluateBudget)
+	router.HandleFunc("POST /api/v1/budgets/{id}/evaluation", handler.NotifyBudget)
+
+	// Каталог сервисов
+	router.HandleFunc("POST /api/v1/services", handler.CreateService)
+	router.HandleFunc("GET /api/v1/services", handler.GetServices)
+	router.HandleFunc("GET /api/v1/services/{id}", handler.GetService)
+	router.HandleFunc("PUT /api/v1/services/{id}", handler.UpdateService)
+	router.HandleFunc("DELETE /api/v1/services/{id}", handler.DeleteService)
+
+	RegisterSwaggerEndpoints(router, docs)
+	RegisterHealthEndpoints(logger, router, checker)
 }
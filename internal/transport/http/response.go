@@ -7,19 +7,6 @@ import (
 	"reflect"
 )
 
-func ResponseWithError(logger *slog.Logger, w http.ResponseWriter, msg string, code int) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(code)
-	resp := errorResponse{
-		Success: false,
-		Error:   msg,
-		Code:    code,
-	}
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		logger.Error("failed on encoding json error: " + err.Error())
-	}
-}
-
 func Response(logger *slog.Logger, w http.ResponseWriter, data any, code int) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
@@ -40,9 +27,3 @@ func Response(logger *slog.Logger, w http.ResponseWriter, data any, code int) {
 		}
 	}
 }
-
-type errorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
-	Code    int    `json:"code"`
-}
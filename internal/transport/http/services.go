@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/IceMAN2377/market/internal/models"
+)
+
+// CreateService добавляет сервис в каталог
+func (h *handler) CreateService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.CreateServiceRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	service, err := h.catalog.CreateService(ctx, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create service", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "service created", "service_id", service.ID, "name", service.Name)
+	Response(h.logger, w, service, http.StatusCreated)
+}
+
+// GetService получает сервис каталога по ID
+func (h *handler) GetService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	service, err := h.catalog.GetServiceByID(ctx, id)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get service", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	Response(h.logger, w, service, http.StatusOK)
+}
+
+// GetServices получает список сервисов каталога
+func (h *handler) GetServices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filters := &models.ServiceFilters{
+		Limit:  10, // значение по умолчанию
+		Offset: 0,  // значение по умолчанию
+	}
+
+	query := r.URL.Query()
+
+	if category := query.Get("category"); category != "" {
+		filters.Category = &category
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filters.Offset = offset
+		}
+	}
+
+	response, err := h.catalog.GetServices(ctx, filters)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to get services", "error", err)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	Response(h.logger, w, response, http.StatusOK)
+}
+
+// UpdateService обновляет название, синонимы, цену по умолчанию или категорию
+func (h *handler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	var req models.UpdateServiceRequest
+	if err := decodeJSON(ctx, r, &req); err != nil {
+		h.respondDecodeError(w, r, err)
+		return
+	}
+
+	// Проверяем, что хотя бы одно поле для обновления указано
+	if req.Name == nil && req.Aliases == nil && req.DefaultPrice == nil && req.Category == nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrNoFieldsToUpdate)
+		return
+	}
+
+	service, err := h.catalog.UpdateService(ctx, id, &req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to update service", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "service updated", "service_id", id)
+	Response(h.logger, w, service, http.StatusOK)
+}
+
+// DeleteService удаляет сервис из каталога; подписки на него остаются
+func (h *handler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		ResponseWithProblem(h.logger, w, r, errs.ErrInvalidID)
+		return
+	}
+
+	if err := h.catalog.DeleteService(ctx, id); err != nil {
+		h.logger.ErrorContext(ctx, "failed to delete service", "error", err, "id", id)
+		ResponseWithProblem(h.logger, w, r, err)
+		return
+	}
+
+	h.logger.InfoContext(ctx, "service deleted", "service_id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,19 +1,36 @@
 package http
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"html/template"
+	"io/fs"
+	"mime"
 	"net/http"
-	"os"
+	"path"
+	"strings"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+	"gopkg.in/yaml.v3"
 )
 
-// HTML шаблон для Swagger UI
+// Префикс, по которому отдаются встроенные ресурсы Swagger UI
+const swaggerAssetsPrefix = "/docs/assets/"
+
+// HTML шаблон для Swagger UI. Ресурсы отдаются самим сервисом,
+// поэтому документация работает без доступа в интернет.
 const swaggerUIHTML = `
 <!DOCTYPE html>
 <html lang="en">
 <head>
     <meta charset="UTF-8">
     <title>Market API Documentation</title>
-    <link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@4.15.5/swagger-ui.css" />
+    <link rel="stylesheet" type="text/css" href="{{.Assets}}swagger-ui.css" />
+    <link rel="icon" type="image/png" href="{{.Assets}}favicon-32x32.png" sizes="32x32" />
+    <link rel="icon" type="image/png" href="{{.Assets}}favicon-16x16.png" sizes="16x16" />
     <style>
         html { box-sizing: border-box; overflow: -moz-scrollbars-vertical; overflow-y: scroll; }
         *, *:before, *:after { box-sizing: inherit; }
@@ -22,8 +39,8 @@ const swaggerUIHTML = `
 </head>
 <body>
     <div id="swagger-ui"></div>
-    <script src="https://unpkg.com/swagger-ui-dist@4.15.5/swagger-ui-bundle.js"></script>
-    <script src="https://unpkg.com/swagger-ui-dist@4.15.5/swagger-ui-standalone-preset.js"></script>
+    <script src="{{.Assets}}swagger-ui-bundle.js"></script>
+    <script src="{{.Assets}}swagger-ui-standalone-preset.js"></script>
     <script>
         window.onload = function() {
             const ui = SwaggerUIBundle({
@@ -46,30 +63,163 @@ const swaggerUIHTML = `
 </html>
 `
 
+// staticContent заранее подготовленный ответ с ETag
+type staticContent struct {
+	data         []byte
+	contentType  string
+	etag         string
+	cacheControl string
+}
+
+func newStaticContent(data []byte, contentType, cacheControl string) *staticContent {
+	sum := sha256.Sum256(data)
+	return &staticContent{
+		data:         data,
+		contentType:  contentType,
+		etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		cacheControl: cacheControl,
+	}
+}
+
+// ServeHTTP отдает содержимое либо 304, если у клиента актуальная копия
+func (c *staticContent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("ETag", c.etag)
+	w.Header().Set("Cache-Control", c.cacheControl)
+
+	if etagMatches(r.Header.Get("If-None-Match"), c.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", c.contentType)
+	w.Write(c.data)
+}
+
+// etagMatches проверяет заголовок If-None-Match (список ETag, слабые ETag или *)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// SwaggerDocs спецификация OpenAPI и страница документации, подготовленные при старте
+type SwaggerDocs struct {
+	page   *staticContent
+	yaml   *staticContent
+	json   *staticContent
+	assets map[string]*staticContent
+}
+
+// NewSwaggerDocs подготавливает страницу Swagger UI, встроенные ресурсы
+// и JSON-версию спецификации, сконвертированную из YAML
+func NewSwaggerDocs(specYAML []byte) (*SwaggerDocs, error) {
+	specJSON, err := yamlToJSON(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert swagger spec to JSON: %w", err)
+	}
+
+	var page bytes.Buffer
+	tmpl := template.Must(template.New("swagger").Parse(swaggerUIHTML))
+	if err := tmpl.Execute(&page, struct{ Assets string }{Assets: swaggerAssetsPrefix}); err != nil {
+		return nil, fmt.Errorf("failed to render swagger page: %w", err)
+	}
+
+	assets, err := loadSwaggerAssets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load swagger ui assets: %w", err)
+	}
+
+	return &SwaggerDocs{
+		page:   newStaticContent(page.Bytes(), "text/html; charset=utf-8", "no-cache"),
+		yaml:   newStaticContent(specYAML, "application/x-yaml", "no-cache"),
+		json:   newStaticContent(specJSON, "application/json", "no-cache"),
+		assets: assets,
+	}, nil
+}
+
+// loadSwaggerAssets читает встроенные файлы swagger-ui-dist (без source map)
+func loadSwaggerAssets() (map[string]*staticContent, error) {
+	assets := make(map[string]*staticContent)
+
+	err := fs.WalkDir(swaggerFiles.FS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(name, ".map") {
+			return err
+		}
+
+		data, err := fs.ReadFile(swaggerFiles.FS, name)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		assets[name] = newStaticContent(data, contentType, "public, max-age=86400")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assets, nil
+}
+
+// yamlToJSON конвертирует YAML документ в JSON
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalizeYAML(doc))
+}
+
+// normalizeYAML приводит ключи отображений к строкам, как того требует JSON
+func normalizeYAML(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalizeYAML(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
 // RegisterSwaggerEndpoints регистрирует эндпоинты для Swagger документации
-func RegisterSwaggerEndpoints(router *http.ServeMux) {
+func RegisterSwaggerEndpoints(router *http.ServeMux, docs *SwaggerDocs) {
 	// Swagger UI интерфейс
-	router.HandleFunc("GET /docs", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		tmpl := template.Must(template.New("swagger").Parse(swaggerUIHTML))
-		tmpl.Execute(w, nil)
-	})
+	router.Handle("GET /docs", docs.page)
 
-	// YAML файл спецификации
-	router.HandleFunc("GET /api/swagger.yaml", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/x-yaml")
-		data, err := os.ReadFile("swagger.yaml")
-		if err != nil {
-			http.Error(w, "Swagger spec not found", http.StatusNotFound)
+	// Встроенные ресурсы Swagger UI
+	router.HandleFunc("GET "+swaggerAssetsPrefix+"{file}", func(w http.ResponseWriter, r *http.Request) {
+		asset, ok := docs.assets[r.PathValue("file")]
+		if !ok {
+			http.NotFound(w, r)
 			return
 		}
-		w.Write(data)
+		asset.ServeHTTP(w, r)
 	})
 
-	// JSON версия спецификации (для совместимости)
-	router.HandleFunc("GET /api/swagger.json", func(w http.ResponseWriter, r *http.Request) {
-		// Здесь можно добавить конвертацию YAML в JSON если нужно
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"info": {"title": "Use /api/swagger.yaml for YAML version"}}`))
-	})
+	// YAML файл спецификации
+	router.Handle("GET /api/swagger.yaml", docs.yaml)
+
+	// JSON версия спецификации
+	router.Handle("GET /api/swagger.json", docs.json)
 }
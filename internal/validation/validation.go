@@ -0,0 +1,205 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/IceMAN2377/market/internal/dates"
+	errs "github.com/IceMAN2377/market/internal/errors"
+	"github.com/go-playground/validator/v10"
+)
+
+// Коды нарушений, не связанные со встроенными тегами validate
+const (
+	CodeDateRange  = "date_range"
+	CodeDate       = "date"
+	CodeWindow     = "window"
+	CodeFutureDate = "future_date"
+	CodePriceRange = "price_range"
+	CodeInteger    = "integer"
+	CodeBoolean    = "boolean"
+)
+
+// FieldError нарушение правила валидации для одного поля
+type FieldError struct {
+	In      string `json:"in,omitempty"` // path, query, body — заполняется при проверке по OpenAPI
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"` // текст на английском; переводится по Key при ответе
+
+	// Ключ сообщения в каталогах i18n и параметры для подстановки
+	Key    string            `json:"-"`
+	Params map[string]string `json:"-"`
+}
+
+// MessageKey возвращает ключ сообщения в каталогах i18n
+func MessageKey(code string) string {
+	return "validation." + code
+}
+
+// Errors все нарушения, найденные при проверке запроса
+type Errors struct {
+	Fields []FieldError
+}
+
+func (e *Errors) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, errs.ErrValidationFailed)
+func (e *Errors) Unwrap() error {
+	return errs.ErrValidationFailed
+}
+
+// Add добавляет нарушение
+func (e *Errors) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message, Key: MessageKey(code)})
+}
+
+// AddParam добавляет нарушение с параметром {param} сообщения, как у тегов min и max
+func (e *Errors) AddParam(field, code, message, param string) {
+	e.Fields = append(e.Fields, FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+		Key:     MessageKey(code),
+		Params:  map[string]string{"param": param},
+	})
+}
+
+// Has сообщает, есть ли уже нарушение для поля
+func (e *Errors) Has(field string) bool {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err возвращает nil, если нарушений нет
+func (e *Errors) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validator проверяет структуры по тегам validate и собирает все нарушения
+type Validator struct {
+	validate *validator.Validate
+	bounds   dates.Bounds
+}
+
+// New создает Validator с пользовательскими правилами (date, window).
+// bounds задает допустимый диапазон лет во входных датах.
+func New(bounds dates.Bounds) *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	// В ошибках используем имена полей из JSON, как их видит клиент
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v := &Validator{validate: validate, bounds: bounds}
+	validate.RegisterValidation(CodeDate, func(fl validator.FieldLevel) bool {
+		return v.Date(fl.Field().String())
+	})
+	validate.RegisterValidation(CodeWindow, func(fl validator.FieldLevel) bool {
+		_, err := dates.ParseWindow(fl.Field().String())
+		return err == nil
+	})
+
+	return v
+}
+
+// Struct проверяет структуру и возвращает все нарушения (пустой список, если их нет)
+func (v *Validator) Struct(s any) *Errors {
+	result := &Errors{}
+
+	err := v.validate.Struct(s)
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fe := range validationErrs {
+			key, params := v.messageKey(fe)
+			result.Fields = append(result.Fields, FieldError{
+				Field:   fe.Field(),
+				Code:    fe.Tag(),
+				Message: v.message(fe),
+				Key:     key,
+				Params:  params,
+			})
+		}
+	} else if err != nil {
+		result.Add("", "invalid", err.Error())
+	}
+
+	return result
+}
+
+// Date проверяет дату в формате MM-YYYY, YYYY-MM или YYYY-MM-DD
+// и допустимый диапазон лет
+func (v *Validator) Date(date string) bool {
+	t, _, err := dates.Parse(date)
+	return err == nil && v.bounds.Contains(t)
+}
+
+// messageKey возвращает ключ сообщения и параметры для каталогов i18n.
+// Для строк min/max ограничивают длину, поэтому у них отдельные ключи.
+func (v *Validator) messageKey(fe validator.FieldError) (string, map[string]string) {
+	switch fe.Tag() {
+	case "min", "max":
+		key := MessageKey(fe.Tag())
+		if fe.Kind() == reflect.String {
+			key += "_length"
+		}
+		return key, map[string]string{"param": fe.Param()}
+	case CodeDate:
+		return MessageKey(CodeDate), map[string]string{
+			"min_year": strconv.Itoa(v.bounds.MinYear),
+			"max_year": strconv.Itoa(v.bounds.MaxYear()),
+		}
+	case CodeWindow:
+		return MessageKey(CodeWindow), map[string]string{"max_days": strconv.Itoa(dates.MaxWindowDays)}
+	default:
+		return MessageKey(fe.Tag()), nil
+	}
+}
+
+// message формирует понятное клиенту описание нарушения
+func (v *Validator) message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "uuid":
+		return "must be a valid UUID"
+	case CodeDate:
+		return fmt.Sprintf("must be a date in MM-YYYY, YYYY-MM or YYYY-MM-DD format with year between %d and %d",
+			v.bounds.MinYear, v.bounds.MaxYear())
+	case CodeWindow:
+		return fmt.Sprintf("must be a number of days (30d) or weeks (4w) up to %d days", dates.MaxWindowDays)
+	default:
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}